@@ -10,19 +10,20 @@ import (
 
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
 	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 )
 
 type CiAttributes = []CiAttribute
 
 type CiAttribute struct {
-	CiID                 int    `json:"ci_id,string"`
-	CiAttributeID        int    `json:"ci_attribute_id,string"`
-	AttributeID          int    `json:"attribute_id,string"`
-	AttributeName        string `json:"attribute_name"`
-	AttributeDescription string `json:"attribute_description"`
-	AttributeType        string `json:"attribute_type"`
-	Value                string `json:"value"`
-	ModifiedAt           string `json:"modified_at"`
+	CiID                 v2.FlexInt  `json:"ci_id"`
+	CiAttributeID        v2.FlexInt  `json:"ci_attribute_id"`
+	AttributeID          v2.FlexInt  `json:"attribute_id"`
+	AttributeName        string      `json:"attribute_name"`
+	AttributeDescription string      `json:"attribute_description"`
+	AttributeType        string      `json:"attribute_type"`
+	Value                string      `json:"value"`
+	ModifiedAt           v2.FlexTime `json:"modified_at"`
 }
 
 type getCiAttributes struct {
@@ -71,21 +72,29 @@ func (c *Client) GetMapOfCiAttributes(ciIds []int) (ciIdToAttributesMap map[int]
 	}
 
 	for _, ciAttribute := range jsonRet.Data {
-		ciAttributes, ok := ciIdToAttributesMap[ciAttribute.CiID]
+		if ciAttribute.AttributeType == ATTRIBUTE_TYPE_NAME_PASSWORD || redact.IsSensitiveKey(ciAttribute.AttributeName) {
+			redact.AddSensitiveValues(ciAttribute.Value)
+		}
+
+		ciAttributes, ok := ciIdToAttributesMap[ciAttribute.CiID.Int()]
 		if !ok {
 			ciAttributes = CiAttributes{}
 		}
 		ciAttributes = append(ciAttributes, ciAttribute)
-		ciIdToAttributesMap[ciAttribute.CiID] = ciAttributes
+		ciIdToAttributesMap[ciAttribute.CiID.Int()] = ciAttributes
 	}
 
 	return
 }
 
 type getAttributeDefaultOption struct {
-	Data []struct {
-		Value string `json:"v"`
-	} `json:"data"`
+	OptionId int `argv:"1"`
+}
+
+func (getAttributeDefaultOption) QueryName() string { return "int_getAttributeDefaultOption" }
+
+type attributeDefaultOptionRow struct {
+	Value string `json:"v"`
 }
 
 func (c *Client) GetAttributeDefaultOption(optionId int) (r string, err error) {
@@ -99,35 +108,23 @@ func (c *Client) GetAttributeDefaultOption(optionId int) (r string, err error) {
 		return cached.(string), nil
 	}
 
-	params := map[string]string{
-		"argv1": strconv.Itoa(optionId),
-	}
-
-	jsonRet := getAttributeDefaultOption{}
-	err = c.v2.Query("int_getAttributeDefaultOption", &jsonRet, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := attributeDefaultOptionRow{}
+	if err = c.queryOne(getAttributeDefaultOption{OptionId: optionId}, &row, strconv.Itoa(optionId)); err != nil {
 		return
 	}
 
-	switch len(jsonRet.Data) {
-	case 0:
-		err = utilError.FunctionError(strconv.Itoa(optionId) + " - " + v2.ErrNoResult.Error())
-	case 1:
-		r = jsonRet.Data[0].Value
-		c.v1.Cache.Set(cacheKey, r, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(strconv.Itoa(optionId) + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	r = row.Value
+	c.v1.Cache.Set(cacheKey, r, utilCache.DefaultExpiration)
 	return
 }
 
 type getAttributeDefaultOptionId struct {
-	Data []responseId `json:"data"`
+	AttributeId int    `argv:"1"`
+	Value       string `argv:"2"`
 }
 
+func (getAttributeDefaultOptionId) QueryName() string { return "int_getAttributeDefaultOptionId" }
+
 func (c *Client) GetAttrDefaultOptionIdByAttrId(attrId int, optionValue string) (attrDefaultOptionId int, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
@@ -141,29 +138,14 @@ func (c *Client) GetAttrDefaultOptionIdByAttrId(attrId int, optionValue string)
 		return cached.(int), nil
 	}
 
-	params := map[string]string{
-		"argv1": attrIdString,
-		"argv2": optionValue,
-	}
-
-	response := getAttributeDefaultOptionId{}
-	err = c.v2.Query("int_getAttributeDefaultOptionId", &response, params)
+	row := responseId{}
+	err = c.queryOne(getAttributeDefaultOptionId{AttributeId: attrId, Value: optionValue}, &row, attrIdString+", "+optionValue)
 	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
 		return
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(attrIdString + ", " + optionValue + " - " + v2.ErrNoResult.Error())
-	case 1:
-		attrDefaultOptionId = response.Data[0].Id
-		c.v2.Cache.Set(cacheKey, attrDefaultOptionId, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(attrIdString + ", " + optionValue + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	attrDefaultOptionId = row.Id.Int()
+	c.v2.Cache.Set(cacheKey, attrDefaultOptionId, utilCache.DefaultExpiration)
 	return
 }
 
@@ -177,43 +159,28 @@ func (c *Client) GetAttrDefaultOptionIdByAttrName(attrName string, optionValue s
 	return
 }
 
-type getAttributeIdByAttributeNameRet struct {
-	Data []responseId `json:"data"`
+type getAttributeIdByAttributeName struct {
+	Name string `argv:"1"`
 }
 
+func (getAttributeIdByAttributeName) QueryName() string { return "int_getAttributeIdByAttributeName" }
+
 func (c *Client) GetAttributeIdByAttributeName(name string) (attrId int, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
 
-	cacheKey := "GetAttributeIdByAttributeName_" + name
-	cached, found := c.v1.Cache.Get(cacheKey)
-	if found {
-		return cached.(int), nil
-	}
-
-	params := map[string]string{
-		"argv1": name,
+	if cached, found := c.metadataCache().Get(METADATA_ATTRIBUTE, name); found {
+		return cached, nil
 	}
 
-	response := getAttributeIdByAttributeNameRet{}
-	err = c.v2.Query("int_getAttributeIdByAttributeName", &response, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := responseId{}
+	if err = c.queryOne(getAttributeIdByAttributeName{Name: name}, &row, name); err != nil {
 		return
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(name + " - " + v2.ErrNoResult.Error())
-	case 1:
-		attrId = response.Data[0].Id
-		c.v1.Cache.Set(cacheKey, attrId, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(name + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	attrId = row.Id.Int()
+	c.metadataCache().Set(METADATA_ATTRIBUTE, name, attrId)
 	return
 }
 
@@ -339,10 +306,67 @@ func (c *Client) GetCiAttributeValueCi(ciId int, attributeName string) (value st
 	return
 }
 
+// UpdateCiAttribute updates the attributes of the ci, values of password attributes are masked in logs and errors.
 func (c *Client) UpdateCiAttribute(ci int, ua []v2.UpdateCiAttribute) (err error) {
+	for _, attribute := range ua {
+		if attribute.Value == "" || redact.IsSensitiveKey(attribute.Name) {
+			continue
+		}
+
+		var attributeType string
+		if attributeType, err = c.GetAttributeTypeNameByAttributeName(attribute.Name); err != nil {
+			return
+		}
+		if attributeType == ATTRIBUTE_TYPE_NAME_PASSWORD {
+			redact.AddSensitiveValues(attribute.Value)
+		}
+	}
+
 	return c.v2.UpdateCiAttribute(ci, ua)
 }
 
+type getAttributeTypeByAttributeName struct {
+	Name string `argv:"1"`
+}
+
+func (getAttributeTypeByAttributeName) QueryName() string {
+	return "int_getAttributeTypeByAttributeName"
+}
+
+type attributeTypeRow struct {
+	Name string `json:"name"`
+}
+
+// GetAttributeTypeNameByAttributeName returns the type of the attribute (e.g. ATTRIBUTE_TYPE_NAME_PASSWORD),
+// an empty name if the attribute doesn't exist.
+func (c *Client) GetAttributeTypeNameByAttributeName(name string) (attributeType string, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	cacheKey := "GetAttributeTypeNameByAttributeName_" + name
+	if cached, found := c.v2.Cache.Get(cacheKey); found {
+		return cached.(string), nil
+	}
+
+	row := attributeTypeRow{}
+	err = c.queryOne(getAttributeTypeByAttributeName{Name: name}, &row, name)
+	if err != nil && !strings.Contains(err.Error(), v2.ErrNoResult.Error()) {
+		return
+	}
+
+	attributeType, err = row.Name, nil
+	c.v2.Cache.Set(cacheKey, attributeType, utilCache.DefaultExpiration)
+	return
+}
+
+// AddSensitiveAttributes marks attributes whose values must never be logged or returned in error messages.
+// Values of password attributes (AT_PASSWORD) are masked automatically when they are read with
+// `GetCiAttributes` or `GetCiDetail` and written with `UpdateCiAttribute` of the Client.
+func (c *Client) AddSensitiveAttributes(attributeNames ...string) {
+	redact.AddSensitiveKeys(attributeNames...)
+}
+
 type AttributeType int
 
 const (
@@ -370,6 +394,12 @@ const (
 	AT_SELECTPOPUP
 )
 
+// Names of attribute types as returned in `CiAttribute.AttributeType`.
+const (
+	ATTRIBUTE_TYPE_NAME_PASSWORD   = "password"
+	ATTRIBUTE_TYPE_NAME_ATTACHMENT = "attachment"
+)
+
 type Columns int
 
 const (
@@ -517,7 +547,8 @@ func (c *Client) CreateAttribute(attributeParams *AttributeParams) (attributeId
 		case 0:
 			err = utilError.FunctionError(attributeParams.Name + " - " + v2.ErrNoResult.Error())
 		case 1:
-			attributeId = response.Data[0].Id
+			attributeId = response.Data[0].Id.Int()
+			c.InvalidateMetadata(METADATA_ATTRIBUTE)
 		default:
 			err = utilError.FunctionError(attributeParams.Name + " - " + v2.ErrTooManyResults.Error())
 		}
@@ -528,39 +559,23 @@ func (c *Client) CreateAttribute(attributeParams *AttributeParams) (attributeId
 	}
 }
 
-type getRoleIdValue struct {
-	Data []struct {
-		RoleId int `json:"id,string"`
-	} `json:"data"`
+type getRoleIdByRoleName struct {
+	Name string `argv:"1"`
 }
 
+func (getRoleIdByRoleName) QueryName() string { return "int_getRoleIdByRoleName" }
+
 func (c *Client) GetRoleIdByName(roleName string) (roleId int, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
 
-	params := map[string]string{
-		"argv1": roleName,
-	}
-
-	response := getRoleIdValue{}
-	err = c.v2.Query("int_getRoleIdByRoleName", &response, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := responseId{}
+	if err = c.queryOne(getRoleIdByRoleName{Name: roleName}, &row, roleName); err != nil {
 		return 0, err
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(roleName + " - " + v2.ErrNoResult.Error())
-	case 1:
-		roleId = response.Data[0].RoleId
-	default:
-		err = utilError.FunctionError(roleName + " - " + v2.ErrTooManyResults.Error())
-	}
-
-	return
+	return row.Id.Int(), nil
 }
 
 func (c *Client) SetAttributeRole(attributeName string, roleName string, permission string) (err error) {
@@ -4,7 +4,6 @@ import (
 	"strconv"
 	"strings"
 
-	utilCache "github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
@@ -13,10 +12,12 @@ import (
 
 var convertBoolToString = map[bool]string{false: "0", true: "1"}
 
-type getAttributeGroupIdValue struct {
-	Data []struct {
-		GroupId int `json:"id,string"`
-	} `json:"data"`
+type getAttributeGroupIdByAttributeGroupName struct {
+	Name string `argv:"1"`
+}
+
+func (getAttributeGroupIdByAttributeGroupName) QueryName() string {
+	return "int_getAttributeGroupIdByAttributeGroupName"
 }
 
 func (c *Client) GetAttributeGroupIdByName(attributeGroupName string) (attGroupId int, err error) {
@@ -24,34 +25,18 @@ func (c *Client) GetAttributeGroupIdByName(attributeGroupName string) (attGroupI
 		return
 	}
 
-	cacheKey := "GetAttributeGroupIdByName_" + attributeGroupName
-	cached, found := c.v1.Cache.Get(cacheKey)
-	if found {
-		return cached.(int), nil
-	}
-
-	params := map[string]string{
-		"argv1": attributeGroupName,
+	if cached, found := c.metadataCache().Get(METADATA_ATTRIBUTE_GROUP, attributeGroupName); found {
+		return cached, nil
 	}
 
-	response := getAttributeGroupIdValue{}
-	err = c.v2.Query("int_getAttributeGroupIdByAttributeGroupName", &response, params)
+	row := responseId{}
+	err = c.queryOne(getAttributeGroupIdByAttributeGroupName{Name: attributeGroupName}, &row, attributeGroupName)
 	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
 		return 0, err
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(attributeGroupName + " - " + v2.ErrNoResult.Error())
-	case 1:
-		attGroupId = response.Data[0].GroupId
-		c.v1.Cache.Set(cacheKey, attGroupId, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(attributeGroupName + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	attGroupId = row.Id.Int()
+	c.metadataCache().Set(METADATA_ATTRIBUTE_GROUP, attributeGroupName, attGroupId)
 	return
 }
 
@@ -134,7 +119,8 @@ func (c *Client) CreateAttributeGroup(attributeGroupParams *AttributeGroupParams
 		case 0:
 			err = utilError.FunctionError(attributeGroupParams.Name + " - " + v2.ErrNoResult.Error())
 		case 1:
-			attributeGroupId = response.Data[0].Id
+			attributeGroupId = response.Data[0].Id.Int()
+			c.InvalidateMetadata(METADATA_ATTRIBUTE_GROUP)
 		default:
 			err = utilError.FunctionError(attributeGroupParams.Name + " - " + v2.ErrTooManyResults.Error())
 		}
@@ -1,9 +1,11 @@
 package infocmdb
 
 import (
+	"strings"
 	"testing"
 
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
 )
 
@@ -142,3 +144,20 @@ func TestInfoCMDB_UpdateCiAttribute(t *testing.T) {
 	}
 }
 
+
+func TestClient_UpdateCiAttribute_password(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/ci/15##{"ci":{"attributes":[{"mode":"set","name":"emp_password","value":"pw-of-ci-15","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	err := cmdb.UpdateCiAttribute(15, []v2.UpdateCiAttribute{{Mode: v2.UPDATE_MODE_SET, Name: "emp_password", Value: "pw-of-ci-15"}})
+	if err != nil {
+		t.Fatalf("UpdateCiAttribute() error = %v", err)
+	}
+	if got := redact.String("password is pw-of-ci-15"); strings.Contains(got, "pw-of-ci-15") {
+		t.Errorf("value of password attribute is not masked: %s", got)
+	}
+}
@@ -0,0 +1,170 @@
+package auth
+
+// Token lifecycle shared by the v1 (apikey) and v2 (api token) authentication.
+//
+// An Authenticator hands out a valid token, logging in when there is none yet or when the current
+// token is about to expire. It is safe for concurrent use: concurrent callers wait for a single login.
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Default lifetime requested when logging in.
+const DefaultLifetime = 600 * time.Second
+
+// Default duration before the expiry of a token in which it is refreshed proactively.
+const DefaultRefreshBefore = 60 * time.Second
+
+var ErrStaticTokenRejected = errors.New("configured api token was rejected")
+
+// Token obtained by a login.
+type Token struct {
+	Value string
+	// Time the token expires, zero if it never expires
+	ExpiresAt time.Time
+}
+
+// LoginFunc performs a login and returns the new token.
+type LoginFunc func() (Token, error)
+
+type Authenticator struct {
+	// Tokens are refreshed if they expire within this duration
+	RefreshBefore time.Duration
+
+	mutex    sync.Mutex
+	login    LoginFunc
+	token    Token
+	static   bool
+	now      func() time.Time
+	store    Store
+	storeKey string
+}
+
+// New returns an authenticator that uses the given function to log in.
+func New(login LoginFunc) *Authenticator {
+	return &Authenticator{
+		RefreshBefore: DefaultRefreshBefore,
+		login:         login,
+		now:           time.Now,
+	}
+}
+
+// NewStatic returns an authenticator for a configured token that never expires and can't be refreshed.
+func NewStatic(token string) *Authenticator {
+	return &Authenticator{
+		token:  Token{Value: token},
+		static: true,
+		now:    time.Now,
+	}
+}
+
+// SetStore persists the tokens in the given store, a valid stored token is used instead of logging in.
+func (a *Authenticator) SetStore(store Store, key string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.static {
+		a.store = store
+		a.storeKey = key
+	}
+}
+
+// Token returns a valid token, logging in if necessary.
+func (a *Authenticator) Token() (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.validToken()
+}
+
+// Refresh discards the given token (e.g. because it was rejected by the api) and returns a valid token.
+// If the token has already been replaced by another caller, the current token is returned without logging in again.
+func (a *Authenticator) Refresh(rejectedToken string) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.token.Value == rejectedToken {
+		if a.static {
+			return "", ErrStaticTokenRejected
+		}
+		a.discard(rejectedToken)
+	}
+
+	return a.validToken()
+}
+
+// Invalidate discards the current token, the next call to `Token` logs in again.
+func (a *Authenticator) Invalidate() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.static {
+		a.discard(a.token.Value)
+	}
+}
+
+// discard removes the token, also from the store unless another process has already replaced it there.
+func (a *Authenticator) discard(token string) {
+	a.token = Token{}
+	if a.store == nil {
+		return
+	}
+
+	stored, ok, err := a.store.Load(a.storeKey)
+	if err == nil && ok && stored.Value != token {
+		return
+	}
+	if err = a.store.Delete(a.storeKey); err != nil {
+		log.Warn("Failed to delete stored token: ", err)
+	}
+}
+
+// validToken returns the current token or logs in, the caller must hold the mutex.
+func (a *Authenticator) validToken() (string, error) {
+	if a.static || a.isValid(a.token) {
+		log.Trace("already logged in")
+		return a.token.Value, nil
+	}
+
+	if a.store != nil {
+		stored, ok, err := a.store.Load(a.storeKey)
+		if err != nil {
+			log.Warn("Failed to load stored token: ", err)
+		} else if ok && a.isValid(stored) {
+			log.Trace("using stored token")
+			a.token = stored
+			return stored.Value, nil
+		}
+	}
+
+	if a.token.Value != "" {
+		log.Debug("Token is about to expire, logging in again")
+	}
+
+	token, err := a.login()
+	if err != nil {
+		return "", err
+	}
+
+	a.token = token
+	if a.store != nil {
+		if err = a.store.Save(a.storeKey, token); err != nil {
+			log.Warn("Failed to store token: ", err)
+		}
+	}
+	return token.Value, nil
+}
+
+func (a *Authenticator) isValid(token Token) bool {
+	if token.Value == "" {
+		return false
+	}
+	if token.ExpiresAt.IsZero() {
+		return true
+	}
+	return a.now().Add(a.RefreshBefore).Before(token.ExpiresAt)
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testLogin struct {
+	mutex  sync.Mutex
+	logins int
+	err    error
+}
+
+func (l *testLogin) login(lifetime time.Duration, now time.Time) LoginFunc {
+	return func() (Token, error) {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		if l.err != nil {
+			return Token{}, l.err
+		}
+		l.logins++
+		return Token{Value: "token" + strconv.Itoa(l.logins), ExpiresAt: now.Add(lifetime)}, nil
+	}
+}
+
+func TestAuthenticator_Token(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		elapsed    time.Duration
+		want       string
+		wantLogins int
+	}{
+		{"valid token is reused", 5 * time.Minute, "token1", 1},
+		{"token is refreshed before expiry", 9*time.Minute + 30*time.Second, "token2", 2},
+		{"expired token is refreshed", 20 * time.Minute, "token2", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &testLogin{}
+			a := New(l.login(DefaultLifetime, start))
+			now := start
+			a.now = func() time.Time { return now }
+
+			if _, err := a.Token(); err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+
+			now = start.Add(tt.elapsed)
+			got, err := a.Token()
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if got != tt.want || l.logins != tt.wantLogins {
+				t.Errorf("Token() = %v with %d logins, want %v with %d logins", got, l.logins, tt.want, tt.wantLogins)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Token_concurrent(t *testing.T) {
+	l := &testLogin{}
+	a := New(l.login(DefaultLifetime, time.Now()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.Token(); err != nil {
+				t.Errorf("Token() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if l.logins != 1 {
+		t.Errorf("concurrent Token() logged in %d times, want 1", l.logins)
+	}
+}
+
+func TestAuthenticator_Refresh(t *testing.T) {
+	l := &testLogin{}
+	a := New(l.login(DefaultLifetime, time.Now()))
+
+	first, _ := a.Token()
+	second, err := a.Refresh(first)
+	if err != nil || second != "token2" {
+		t.Fatalf("Refresh() = %v, %v, want token2", second, err)
+	}
+
+	// the rejected token has already been replaced, no further login
+	third, err := a.Refresh(first)
+	if err != nil || third != "token2" || l.logins != 2 {
+		t.Errorf("Refresh() of replaced token = %v with %d logins, want token2 with 2 logins", third, l.logins)
+	}
+}
+
+func TestAuthenticator_loginError(t *testing.T) {
+	l := &testLogin{err: errors.New("login failed")}
+	a := New(l.login(DefaultLifetime, time.Now()))
+
+	if _, err := a.Token(); err == nil {
+		t.Error("Token() expected error")
+	}
+
+	l.err = nil
+	if got, err := a.Token(); err != nil || got != "token1" {
+		t.Errorf("Token() after failed login = %v, %v, want token1", got, err)
+	}
+}
+
+func TestNewStatic(t *testing.T) {
+	a := NewStatic("static")
+	a.Invalidate()
+
+	if got, err := a.Token(); err != nil || got != "static" {
+		t.Errorf("Token() = %v, %v, want static", got, err)
+	}
+	if _, err := a.Refresh("static"); err != ErrStaticTokenRejected {
+		t.Errorf("Refresh() error = %v, want %v", err, ErrStaticTokenRejected)
+	}
+}
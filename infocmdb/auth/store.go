@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Store persists tokens, e.g. to share them between short-lived workflow processes.
+type Store interface {
+	Load(key string) (token Token, ok bool, err error)
+	Save(key string, token Token) error
+	Delete(key string) error
+}
+
+// StoreKey returns the key of the token of a user for an api url.
+func StoreKey(apiUrl string, username string) string {
+	hash := sha256.Sum256([]byte(apiUrl + "\x00" + username))
+	return hex.EncodeToString(hash[:])
+}
+
+// FileStore stores each token in a separate file (permissions 0600) in the given directory.
+type FileStore struct {
+	Dir string
+}
+
+type storedToken struct {
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewFileStore returns a store for the given directory, it is created on the first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.Dir, "token-"+key+".json")
+}
+
+func (s *FileStore) Load(key string) (token Token, ok bool, err error) {
+	content, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return token, false, nil
+	}
+	if err != nil {
+		return
+	}
+
+	var stored storedToken
+	if err = json.Unmarshal(content, &stored); err != nil {
+		return
+	}
+
+	return Token{Value: stored.Value, ExpiresAt: stored.ExpiresAt}, stored.Value != "", nil
+}
+
+// Save writes the token to a temporary file and renames it, concurrent processes never read a partial file.
+func (s *FileStore) Save(key string, token Token) (err error) {
+	if err = os.MkdirAll(s.Dir, 0700); err != nil {
+		return
+	}
+
+	content, err := json.Marshal(storedToken{Value: token.Value, ExpiresAt: token.ExpiresAt})
+	if err != nil {
+		return
+	}
+
+	file, err := ioutil.TempFile(s.Dir, "token-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(file.Name())
+
+	if err = file.Chmod(0600); err != nil {
+		file.Close()
+		return
+	}
+	if _, err = file.Write(content); err != nil {
+		file.Close()
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(file.Name(), s.path(key))
+}
+
+func (s *FileStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
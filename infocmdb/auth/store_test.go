@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*FileStore, func()) {
+	dir, err := ioutil.TempDir("", "infocmdb-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFileStore(filepath.Join(dir, "tokens")), func() { os.RemoveAll(dir) }
+}
+
+func TestFileStore(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	key := StoreKey("http://localhost", "admin")
+	if _, ok, err := store.Load(key); ok || err != nil {
+		t.Fatalf("Load() of missing token = %v, %v, want not ok", ok, err)
+	}
+
+	token := Token{Value: "token", ExpiresAt: time.Now().Add(time.Hour).Round(time.Second)}
+	if err := store.Save(key, token); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(store.path(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("token file permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	got, ok, err := store.Load(key)
+	if err != nil || !ok || got.Value != token.Value || !got.ExpiresAt.Equal(token.ExpiresAt) {
+		t.Errorf("Load() = %+v, %v, %v, want %+v", got, ok, err, token)
+	}
+
+	if _, ok, _ := store.Load(StoreKey("http://localhost", "other")); ok {
+		t.Error("Load() returned token of other user")
+	}
+
+	if err = store.Delete(key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := store.Load(key); ok {
+		t.Error("Load() after Delete() returned token")
+	}
+}
+
+func TestAuthenticator_SetStore(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	key := StoreKey("http://localhost", "admin")
+
+	l := &testLogin{}
+	first := New(l.login(DefaultLifetime, time.Now()))
+	first.SetStore(store, key)
+	if got, err := first.Token(); err != nil || got != "token1" {
+		t.Fatalf("Token() = %v, %v, want token1", got, err)
+	}
+
+	// a second process reuses the stored token
+	second := New(l.login(DefaultLifetime, time.Now()))
+	second.SetStore(store, key)
+	if got, err := second.Token(); err != nil || got != "token1" || l.logins != 1 {
+		t.Fatalf("Token() with stored token = %v with %d logins, want token1 with 1 login", got, l.logins)
+	}
+
+	// a rejected token is removed from the store
+	if got, err := second.Refresh("token1"); err != nil || got != "token2" {
+		t.Fatalf("Refresh() = %v, %v, want token2", got, err)
+	}
+	if stored, _, _ := store.Load(key); stored.Value != "token2" {
+		t.Errorf("stored token = %v, want token2", stored.Value)
+	}
+}
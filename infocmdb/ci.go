@@ -22,10 +22,17 @@ type Ci struct {
 	ProjectIDs         []int
 }
 
+// ciRow decodes the ids of a ci as numbers or numeric strings
+type ciRow struct {
+	CiID               v2.FlexInt `json:"ci_id"`
+	CiTypeID           v2.FlexInt `json:"ci_type_id"`
+	CiType             string     `json:"ci_type"`
+	ProjectsAsString   string     `json:"project"`
+	ProjectIDsAsString string     `json:"project_id"`
+}
+
 type getCi struct {
-	Data []struct {
-		Ci
-	} `json:"data"`
+	Data []ciRow `json:"data"`
 }
 
 func (c *Client) GetCi(ciID int) (r Ci, err error) {
@@ -49,7 +56,14 @@ func (c *Client) GetCi(ciID int) (r Ci, err error) {
 	case 0:
 		err = utilError.FunctionError(strconv.Itoa(ciID) + " - " + v2.ErrNoResult.Error())
 	case 1:
-		r = jsonRet.Data[0].Ci
+		row := jsonRet.Data[0]
+		r = Ci{
+			CiID:               row.CiID.Int(),
+			CiTypeID:           row.CiTypeID.Int(),
+			CiType:             row.CiType,
+			ProjectsAsString:   row.ProjectsAsString,
+			ProjectIDsAsString: row.ProjectIDsAsString,
+		}
 	default:
 		err = utilError.FunctionError(strconv.Itoa(ciID) + " - " + v2.ErrTooManyResults.Error())
 	}
@@ -68,7 +82,7 @@ type CiIds []int
 
 type getListOfCiIdsOfCiType struct {
 	Data []struct {
-		CiID int `json:"ciid,string"`
+		CiID v2.FlexInt `json:"ciid"`
 	} `json:"data"`
 }
 
@@ -93,7 +107,7 @@ func (c *Client) GetListOfCiIdsOfCiType(ciTypeID int) (ciIds CiIds, err error) {
 	}
 
 	for _, ciIdOfCiType := range ret.Data {
-		ciIds = append(ciIds, ciIdOfCiType.CiID)
+		ciIds = append(ciIds, ciIdOfCiType.CiID.Int())
 	}
 
 	return
@@ -120,7 +134,7 @@ func (c *Client) GetListOfCiIdsOfCiTypeV2(ciTypeID int) (ciIds CiIds, err error)
 	}
 
 	for _, ciIdOfCiType := range ret.Data {
-		ciIds = append(ciIds, ciIdOfCiType.CiID)
+		ciIds = append(ciIds, ciIdOfCiType.CiID.Int())
 	}
 
 	return
@@ -143,7 +157,7 @@ func (c *Client) GetListOfCiIdsOfCiTypeName(ciTypeName string) (ciIds CiIds, err
 
 type getListOfCiIdsByAttributeValue struct {
 	Data []struct {
-		CiID int `json:"ci_id,string"`
+		CiID v2.FlexInt `json:"ci_id"`
 	} `json:"data"`
 }
 
@@ -171,7 +185,7 @@ func (c *Client) GetListOfCiIdsByAttributeValue(name string, value string, value
 	}
 
 	for _, ciId := range ret.Data {
-		ciIds = append(ciIds, ciId.CiID)
+		ciIds = append(ciIds, ciId.CiID.Int())
 	}
 
 	return
@@ -179,7 +193,7 @@ func (c *Client) GetListOfCiIdsByAttributeValue(name string, value string, value
 
 type getListOfCiIdsByCiRelation struct {
 	Data []struct {
-		CiId int `json:"ci_id,string"`
+		CiId v2.FlexInt `json:"ci_id"`
 	} `json:"data"`
 }
 
@@ -225,7 +239,7 @@ func (c *Client) GetListOfCiIdsByCiRelation(ciId int, ciRelationTypeName string,
 	}
 
 	for _, row := range jsonRet.Data {
-		r = append(r, row.CiId)
+		r = append(r, row.CiId.Int())
 	}
 
 	return
@@ -240,40 +254,46 @@ type CreateCi struct {
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
-type createCiResponse struct {
-	Data []struct {
-		CreateCi
-	} `json:"data"`
+
+// createCiRow decodes the ids of the created ci as numbers or numeric strings
+type createCiRow struct {
+	ID        v2.FlexInt `json:"id"`
+	CiTypeID  v2.FlexInt `json:"ci_type_id"`
+	Icon      string     `json:"icon"`
+	HistoryID v2.FlexInt `json:"history_id"`
+	ValidFrom string     `json:"valid_from"`
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt string     `json:"updated_at"`
+}
+
+type createCi struct {
+	CiTypeId  int    `argv:"1"`
+	Icon      string `argv:"2"`
+	HistoryId int    `argv:"3"`
 }
 
+func (createCi) QueryName() string { return "int_createCi" }
+
 func (c *Client) CreateCi(ciTypeID int, icon string, historyID int) (r CreateCi, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
 
-	params := map[string]string{
-		"argv1": strconv.Itoa(ciTypeID),
-		"argv2": icon,
-		"argv3": strconv.Itoa(historyID),
-	}
-
-	jsonRet := createCiResponse{}
-	err = c.v2.Query("int_createCi", &jsonRet, params)
+	row := createCiRow{}
+	err = c.queryOne(createCi{CiTypeId: ciTypeID, Icon: icon, HistoryId: historyID}, &row, strconv.Itoa(ciTypeID))
 	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
-		return r, err
+		return
 	}
 
-	switch len(jsonRet.Data) {
-	case 0:
-		err = utilError.FunctionError(strconv.Itoa(ciTypeID) + " - " + v2.ErrNoResult.Error())
-	case 1:
-		r = jsonRet.Data[0].CreateCi
-	default:
-		err = utilError.FunctionError(strconv.Itoa(ciTypeID) + " - " + v2.ErrTooManyResults.Error())
+	r = CreateCi{
+		ID:        row.ID.Int(),
+		CiTypeID:  row.CiTypeID.Int(),
+		Icon:      row.Icon,
+		HistoryID: row.HistoryID.Int(),
+		ValidFrom: row.ValidFrom,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
 	}
-
 	return
 }
 
@@ -0,0 +1,177 @@
+package infocmdb
+
+import (
+	"errors"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type CloneOptions struct {
+	// Attributes that are not copied, e.g. identifiers
+	ExcludeAttributes []string
+	// Don't copy attributes that are defined as unique
+	ExcludeUnique bool
+	// Create the relations of the ci for the clone as well
+	CopyRelations bool
+	// User the history entry is created for, also used to delete the clone if cloning fails
+	UserId int
+	// Message of the history entry the changes are grouped under, none is created if empty
+	HistoryMessage string
+}
+
+// CloneCi creates a new ci with the type, projects and attribute values of the given ci.
+//
+// Overrides replace all values of an attribute, an empty override leaves the attribute empty.
+// Attachments are not copied. If any step after creating the clone fails, the clone is deleted again.
+func (c *Client) CloneCi(ciId int, overrides map[string]string, opts CloneOptions) (cloneId int, err error) {
+	if opts.UserId == 0 {
+		return 0, errors.New("missing userId")
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	ci, err := c.GetCi(ciId)
+	if err != nil {
+		return
+	}
+	attributes, err := c.GetCiAttributes(ciId)
+	if err != nil {
+		return
+	}
+
+	excluded := map[string]bool{}
+	for _, name := range opts.ExcludeAttributes {
+		excluded[name] = true
+	}
+	if opts.ExcludeUnique {
+		var unique []string
+		if unique, err = c.getUniqueAttributeNames(); err != nil {
+			return
+		}
+		for _, name := range unique {
+			excluded[name] = true
+		}
+	}
+
+	var updates []v2.UpdateCiAttribute
+	for _, attribute := range attributes {
+		_, overridden := overrides[attribute.AttributeName]
+		if overridden || excluded[attribute.AttributeName] || attribute.AttributeType == ATTRIBUTE_TYPE_NAME_ATTACHMENT || attribute.Value == "" {
+			continue
+		}
+		updates = append(updates, v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_INSERT, Name: attribute.AttributeName, Value: attribute.Value})
+	}
+	for _, name := range sortedNames(overrides) {
+		if overrides[name] == "" {
+			continue
+		}
+		updates = append(updates, v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_SET, Name: name, Value: overrides[name]})
+	}
+
+	var relations []Relation
+	if opts.CopyRelations {
+		if relations, err = c.GetCiRelations(ciId); err != nil {
+			return
+		}
+	}
+
+	historyId := 0
+	if opts.HistoryMessage != "" {
+		if historyId, err = c.CreateHistory(opts.UserId, opts.HistoryMessage); err != nil {
+			return
+		}
+	}
+
+	clone, err := c.CreateCi(ci.CiTypeID, "", historyId)
+	if err != nil {
+		return
+	}
+	cloneId = clone.ID
+
+	if err = c.completeClonedCi(ciId, cloneId, positiveIds(ci.ProjectIDs), updates, relations, historyId); err != nil {
+		return 0, c.rollbackCreatedCi(cloneId, opts.UserId, err)
+	}
+
+	return
+}
+
+func (c *Client) completeClonedCi(ciId int, cloneId int, projectIds []int, updates []v2.UpdateCiAttribute, relations []Relation, historyId int) (err error) {
+	if err = c.completeCreatedCi(cloneId, projectIds, nil, historyId); err != nil {
+		return
+	}
+
+	if len(updates) > 0 {
+		if err = c.UpdateCiAttribute(cloneId, updates); err != nil {
+			return
+		}
+	}
+
+	for _, relation := range relations {
+		if err = c.copyRelation(relation, ciId, cloneId); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// copyRelation creates the relation for another ci, in the same column and with the same attributes.
+// Relations of the ci with itself are skipped.
+func (c *Client) copyRelation(relation Relation, fromCiId int, toCiId int) error {
+	if relation.RelationTypeName == "" {
+		return utilError.FunctionError(strconv.Itoa(relation.Id) + " - relation without relation type name")
+	}
+
+	ciId1, ciId2 := relation.CiId1, relation.CiId2
+	if ciId1 == fromCiId {
+		ciId1 = toCiId
+	} else {
+		ciId2 = toCiId
+	}
+	if ciId1 == ciId2 {
+		return nil
+	}
+
+	return c.CreateCiRelationWithAttributes(ciId1, ciId2, relation.RelationTypeName, relation.Direction, RelationAttributes{
+		Weighting: relation.Weighting,
+		Color:     relation.Color,
+		Note:      relation.Note,
+	})
+}
+
+type getUniqueAttributeNames struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+func (c *Client) getUniqueAttributeNames() (names []string, err error) {
+	jsonRet := getUniqueAttributeNames{}
+	err = c.v2.Query("int_getUniqueAttributes", &jsonRet, map[string]string{})
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	for _, row := range jsonRet.Data {
+		names = append(names, row.Name)
+	}
+	return
+}
+
+// positiveIds removes the zero ids `GetCi` returns for cis without projects.
+func positiveIds(ids []int) (positive []int) {
+	for _, id := range ids {
+		if id > 0 {
+			positive = append(positive, id)
+		}
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package infocmdb
+
+import (
+	"net/http"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_CloneCi(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"436","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"436","attribute_name":"emp_staff_number","attribute_type":"input","value":"91654"},
+				{"ci_id":"436","attribute_name":"emp_firstname","attribute_type":"input","value":"Ralph"},
+				{"ci_id":"436","attribute_name":"emp_lastname","attribute_type":"input","value":"Wiggum"},
+				{"ci_id":"436","attribute_name":"emp_photo","attribute_type":"attachment","value":"ralph.png"},
+				{"ci_id":"436","attribute_name":"emp_phone","attribute_type":"input","value":"0664 1"},
+				{"ci_id":"436","attribute_name":"emp_phone","attribute_type":"input","value":"0664 2"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getUniqueAttributes##{"query":{"params":{}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"emp_staff_number"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"1","ci_id_1":"436","ci_id_2":"500","direction":"4","ci_relation_type_id":"30","ci_relation_type_name":"depends_on","weighting":"1"},` +
+				`{"id":"2","ci_id_1":"501","ci_id_2":"436","direction":"2","ci_relation_type_id":"31","ci_relation_type_name":"runs_on"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"clone of 436"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"1001"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"1001"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"617900","ci_type_id":"12","icon":"","history_id":"1001"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"617900","argv2":"33","argv3":"1001"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/617900##{"ci":{"attributes":[` +
+				`{"mode":"insert","name":"emp_lastname","value":"Wiggum","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_phone","value":"0664 1","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_phone","value":"0664 2","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"set","name":"emp_firstname","value":"Lisa","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString: emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"617900","argv2":"500","argv3":"30"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelationWithAttributes##` +
+				`{"query":{"params":{"argv1":"617900","argv2":"500","argv3":"30","argv4":"4","argv5":"1","argv6":"","argv7":""}}}`,
+			ReturnString: emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"501","argv2":"617900","argv3":"31"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelationWithAttributes##` +
+				`{"query":{"params":{"argv1":"501","argv2":"617900","argv3":"31","argv4":"2","argv5":"0","argv6":"","argv7":""}}}`,
+			ReturnString: emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"617901","ci_type_id":"12","icon":"","history_id":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"617901","argv2":"33","argv3":"0"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/617901##{"ci":{"attributes":[` +
+				`{"mode":"insert","name":"emp_staff_number","value":"91654","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_firstname","value":"Ralph","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_lastname","value":"Wiggum","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_phone","value":"0664 1","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"insert","name":"emp_phone","value":"0664 2","ciAttributeId":0,"uploadId":""}]}}`,
+			StatusCode: http.StatusBadRequest,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCi##{"query":{"params":{"argv1":"617901","argv2":"7","argv3":"rollback of failed ci creation"}}}`,
+			ReturnString:  emptyResult,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "depends_on", 30)
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "runs_on", 31)
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		opts      CloneOptions
+		want      int
+		wantErr   bool
+	}{
+		{
+			name:      "clone with relations",
+			overrides: map[string]string{"emp_firstname": "Lisa"},
+			opts:      CloneOptions{ExcludeUnique: true, CopyRelations: true, UserId: 7, HistoryMessage: "clone of 436"},
+			want:      617900,
+		},
+		{
+			name:    "rollback",
+			opts:    CloneOptions{UserId: 7},
+			wantErr: true,
+		},
+		{
+			name:    "missing user",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.CloneCi(436, tt.overrides, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CloneCi() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CloneCi() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package infocmdb
+
+import (
+	"errors"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// CreateCiRequest describes a ci to create with `CreateCiWithAttributes`.
+type CreateCiRequest struct {
+	// Name of the ci type
+	CiType string
+	// Names of the projects the ci is added to, at least one is required
+	Projects []string
+	// Attribute values by attribute name
+	Attributes map[string]string
+	Icon       string
+	// Message of the history entry the changes are grouped under, none is created if empty
+	HistoryMessage string
+	// User the history entry is created for, also used to delete the ci if the creation fails
+	UserId int
+}
+
+// CreateCiWithAttributes creates a ci, adds it to its projects and sets its attributes.
+//
+// If any step after creating the ci fails, the ci is deleted again with `DeleteCi`. The rollback covers only the ci
+// itself: project mappings and attribute values that were already added are removed only as far as `int_deleteCi`
+// removes them together with the ci, and the history entry of `HistoryMessage` is kept, so the history shows both
+// the failed creation and the rollback.
+func (c *Client) CreateCiWithAttributes(request CreateCiRequest) (ciId int, err error) {
+	if request.UserId == 0 {
+		return 0, errors.New("missing userId")
+	}
+	if len(request.Projects) == 0 {
+		return 0, utilError.FunctionError(request.CiType + " - no project given")
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	ciTypeId, err := c.GetCiTypeIdByCiTypeName(request.CiType)
+	if err != nil {
+		return
+	}
+
+	projectIds := make([]int, len(request.Projects))
+	for i, project := range request.Projects {
+		if projectIds[i], err = c.GetProjectIdByProjectName(project); err != nil {
+			return
+		}
+	}
+
+	historyId := 0
+	if request.HistoryMessage != "" {
+		if historyId, err = c.CreateHistory(request.UserId, request.HistoryMessage); err != nil {
+			return
+		}
+	}
+
+	ci, err := c.CreateCi(ciTypeId, request.Icon, historyId)
+	if err != nil {
+		return
+	}
+	ciId = ci.ID
+
+	if err = c.completeCreatedCi(ciId, projectIds, request.Attributes, historyId); err != nil {
+		return 0, c.rollbackCreatedCi(ciId, request.UserId, err)
+	}
+
+	return
+}
+
+func (c *Client) completeCreatedCi(ciId int, projectIds []int, attributes map[string]string, historyId int) (err error) {
+	for _, projectId := range projectIds {
+		if err = c.AddCiProjectMapping(ciId, projectId, historyId); err != nil {
+			return
+		}
+	}
+
+	if len(attributes) == 0 {
+		return
+	}
+
+	names := sortedNames(attributes)
+	updates := make([]v2.UpdateCiAttribute, len(names))
+	for i, name := range names {
+		updates[i] = v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_SET, Name: name, Value: attributes[name]}
+	}
+
+	return c.UpdateCiAttribute(ciId, updates)
+}
+
+// rollbackCreatedCi deletes a ci whose creation failed, the returned error contains the cause and a failed deletion.
+// Project mappings and the history entry are not removed separately, there are no webservices to do so.
+func (c *Client) rollbackCreatedCi(ciId int, userId int, cause error) error {
+	log.Warnf("Creation of ci %d failed, deleting it: %v", ciId, cause)
+
+	errs := utilError.Errors{}.Add(utilError.FunctionError(strconv.Itoa(ciId) + " - " + cause.Error()))
+	if err := c.DeleteCi(ciId, userId, "rollback of failed ci creation"); err != nil {
+		errs = errs.Add(utilError.FunctionError(strconv.Itoa(ciId) + " - rollback failed: " + err.Error()))
+	}
+
+	log.Error("Error: ", errs)
+	return errs
+}
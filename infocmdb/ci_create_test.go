@@ -0,0 +1,102 @@
+package infocmdb
+
+import (
+	"net/http"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_CreateCiWithAttributes(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"import employees"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"59529030"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"59529030"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"617831","ci_type_id":"12","icon":"","history_id":"59529030"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"617832","ci_type_id":"12","icon":"","history_id":"59529031"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"617831","argv2":"33","argv3":"59529030"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":null}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"617832","argv2":"33","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":null}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/617831##{"ci":{"attributes":[{"mode":"set","name":"emp_firstname","value":"Cornelia","ciAttributeId":0,"uploadId":""},{"mode":"set","name":"emp_lastname","value":"Blank","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/617832##{"ci":{"attributes":[{"mode":"set","name":"emp_lastname_NOT_EXISTING","value":"Blank","ciAttributeId":0,"uploadId":""}]}}`,
+			StatusCode:    http.StatusBadRequest,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCi##{"query":{"params":{"argv1":"617832","argv2":"7","argv3":"rollback of failed ci creation"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "emp_germany_berlin", 12)
+	cmdb.metadataCache().Set(METADATA_PROJECT, "springfield", 33)
+
+	tests := []struct {
+		name    string
+		request CreateCiRequest
+		want    int
+		wantErr bool
+	}{
+		{
+			"valid",
+			CreateCiRequest{
+				CiType:         "emp_germany_berlin",
+				Projects:       []string{"springfield"},
+				Attributes:     map[string]string{"emp_lastname": "Blank", "emp_firstname": "Cornelia"},
+				HistoryMessage: "import employees",
+				UserId:         7,
+			},
+			617831,
+			false,
+		},
+		{
+			"invalid attribute is rolled back",
+			CreateCiRequest{
+				CiType:     "emp_germany_berlin",
+				Projects:   []string{"springfield"},
+				Attributes: map[string]string{"emp_lastname_NOT_EXISTING": "Blank"},
+				UserId:     7,
+			},
+			0,
+			true,
+		},
+		{
+			"missing user",
+			CreateCiRequest{CiType: "emp_germany_berlin", Projects: []string{"springfield"}},
+			0,
+			true,
+		},
+		{
+			"missing project",
+			CreateCiRequest{CiType: "emp_germany_berlin", UserId: 7},
+			0,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.CreateCiWithAttributes(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateCiWithAttributes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateCiWithAttributes() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package infocmdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
+)
+
+// CiDetail is the detail view of a ci as shown in the infoCMDB.
+type CiDetail struct {
+	Id        int
+	Icon      string
+	HistoryId int
+	ValidFrom time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	CiType   CiTypeInfo
+	Projects []ProjectInfo
+	// Attribute groups ordered by id, their attributes by order number
+	AttributeGroups []AttributeGroup
+	// Path of ci types from the root ci type to the ci type of the ci
+	Breadcrumbs []Breadcrumb
+	Events      []Event
+}
+
+type CiTypeInfo struct {
+	Id                int
+	Name              string
+	Description       string
+	Note              string
+	ParentCiTypeId    int
+	Icon              string
+	DefaultProjectId  int
+	IsCiAttach        bool
+	IsAttributeAttach bool
+	IsTabEnabled      bool
+	IsEventEnabled    bool
+	IsActive          bool
+}
+
+type ProjectInfo struct {
+	Id          int
+	Name        string
+	Description string
+	// Time the ci was added to the project
+	ValidFrom time.Time
+}
+
+type AttributeGroup struct {
+	Id          int
+	Name        string
+	Description string
+	Columns     int
+	Attributes  []AttributeValue
+	// Number of attributes the current user may read or write
+	ReadCount  int
+	WriteCount int
+}
+
+// AttributeValue is a single value of a ci attribute, multi-value attributes have one AttributeValue per value.
+type AttributeValue struct {
+	CiAttributeId     int
+	AttributeId       int
+	Name              string
+	Description       string
+	AttributeTypeName string
+	AttributeGroupId  int
+	AttributeGroup    string
+	OrderNumber       int
+	HistoryId         int
+	Initial           bool
+	ValueText         v2.NullString
+	ValueDate         time.Time
+	ValueCi           int
+	ValueNote         string
+	// Text of the selected option of select and default-valued attributes
+	ValueDefault v2.NullString
+	// Current user may change the value
+	Writable bool
+}
+
+type Breadcrumb struct {
+	CiTypeId    int
+	Name        string
+	Description string
+	CrumbType   string
+}
+
+// Event is the value of an event attribute of the ci.
+type Event struct {
+	AttributeValue
+}
+
+// Value returns the value as string: the text, the date, the referenced ci id or the default value, whichever is set.
+func (a AttributeValue) Value() string {
+	switch {
+	case a.ValueText.Valid:
+		return a.ValueText.String
+	case !a.ValueDate.IsZero():
+		return v2.FlexTime{Time: a.ValueDate}.String()
+	case a.ValueCi != 0:
+		return strconv.Itoa(a.ValueCi)
+	case a.ValueDefault.Valid:
+		return a.ValueDefault.String
+	}
+	return ""
+}
+
+// CanRead returns true if the current user may read at least one attribute of the group.
+func (g AttributeGroup) CanRead() bool {
+	return g.ReadCount > 0
+}
+
+// CanWrite returns true if the current user may write at least one attribute of the group.
+func (g AttributeGroup) CanWrite() bool {
+	return g.WriteCount > 0
+}
+
+// Attributes returns the values of all attribute groups.
+func (d CiDetail) Attributes() (attributes []AttributeValue) {
+	for _, group := range d.AttributeGroups {
+		attributes = append(attributes, group.Attributes...)
+	}
+	return
+}
+
+// AttributeByName returns the first value of the attribute.
+func (d CiDetail) AttributeByName(name string) (attribute AttributeValue, found bool) {
+	for _, group := range d.AttributeGroups {
+		for _, attribute = range group.Attributes {
+			if attribute.Name == name {
+				return attribute, true
+			}
+		}
+	}
+	return AttributeValue{}, false
+}
+
+// ValueByName returns the first value of the attribute.
+func (d CiDetail) ValueByName(name string) (value string, found bool) {
+	attribute, found := d.AttributeByName(name)
+	return attribute.Value(), found
+}
+
+// ValuesByName returns all values of a multi-value attribute.
+func (d CiDetail) ValuesByName(name string) (values []string) {
+	for _, attribute := range d.Attributes() {
+		if attribute.Name == name {
+			values = append(values, attribute.Value())
+		}
+	}
+	return
+}
+
+// AttributesInGroup returns the attribute values of the attribute group with the given name.
+func (d CiDetail) AttributesInGroup(groupName string) []AttributeValue {
+	for _, group := range d.AttributeGroups {
+		if group.Name == groupName {
+			return group.Attributes
+		}
+	}
+	return nil
+}
+
+// CanWrite returns true if the current user may change the attribute.
+func (d CiDetail) CanWrite(attributeName string) bool {
+	attribute, found := d.AttributeByName(attributeName)
+	return found && attribute.Writable
+}
+
+// ProjectIds returns the ids of the projects the ci belongs to.
+func (d CiDetail) ProjectIds() (projectIds []int) {
+	for _, project := range d.Projects {
+		projectIds = append(projectIds, project.Id)
+	}
+	return
+}
+
+// GetCiDetail returns the ci with its ci type, projects, attributes, breadcrumbs and events.
+func (c *Client) GetCiDetail(ciId int) (ciDetail CiDetail, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	resp, _, err := c.v2.CiDetailByCiId(int64(ciId))
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+	if !resp.Success {
+		err = utilError.FunctionError(strconv.Itoa(ciId) + " - " + resp.Message)
+		log.Error("Error: ", err)
+		return
+	}
+
+	return newCiDetail(resp), nil
+}
+
+func newCiDetail(resp v2.GetCiDetailResponse) (ciDetail CiDetail) {
+	data := resp.Data.Data
+
+	ciDetail = CiDetail{
+		Id:        data.Ci.ID.Int(),
+		Icon:      data.Ci.Icon,
+		HistoryId: data.Ci.HistoryID.Int(),
+		ValidFrom: data.Ci.ValidFrom.Time,
+		CreatedAt: data.Ci.CreatedAt.Time,
+		UpdatedAt: data.Ci.UpdatedAt.Time,
+		CiType: CiTypeInfo{
+			Id:                data.CiType.ID.Int(),
+			Name:              data.CiType.Name,
+			Description:       data.CiType.Description,
+			Note:              data.CiType.Note,
+			ParentCiTypeId:    data.CiType.ParentCiTypeID.Int(),
+			Icon:              data.CiType.Icon,
+			DefaultProjectId:  data.CiType.DefaultProjectID.Int(),
+			IsCiAttach:        data.CiType.IsCiAttach.Bool(),
+			IsAttributeAttach: data.CiType.IsAttributeAttach.Bool(),
+			IsTabEnabled:      data.CiType.IsTabEnabled.Bool(),
+			IsEventEnabled:    data.CiType.IsEventEnabled.Bool(),
+			IsActive:          data.CiType.IsActive.Bool(),
+		},
+	}
+
+	for _, project := range data.ProjectList {
+		ciDetail.Projects = append(ciDetail.Projects, ProjectInfo{
+			Id:          project.ID.Int(),
+			Name:        project.Name,
+			Description: project.Description,
+			ValidFrom:   project.CiProjectValidFrom.Time,
+		})
+	}
+
+	for _, group := range data.AttributeList {
+		attributeGroup := AttributeGroup{
+			Id:          group.ID.Int(),
+			Name:        group.Name,
+			Description: group.Description,
+			Columns:     group.Columns,
+			ReadCount:   group.ReadCount,
+			WriteCount:  group.WriteCount,
+		}
+		for _, values := range group.Attributes {
+			for _, value := range values {
+				attributeGroup.Attributes = append(attributeGroup.Attributes, newAttributeValue(value))
+			}
+		}
+		sortAttributeValues(attributeGroup.Attributes)
+		ciDetail.AttributeGroups = append(ciDetail.AttributeGroups, attributeGroup)
+	}
+	sort.Slice(ciDetail.AttributeGroups, func(i, j int) bool {
+		return ciDetail.AttributeGroups[i].Id < ciDetail.AttributeGroups[j].Id
+	})
+
+	for _, crumb := range data.Breadcrumbs {
+		ciDetail.Breadcrumbs = append(ciDetail.Breadcrumbs, Breadcrumb{
+			CiTypeId:    crumb.ID.Int(),
+			Name:        crumb.Name,
+			Description: crumb.Description.String,
+			CrumbType:   crumb.CrumbType,
+		})
+	}
+
+	for _, event := range data.Events {
+		ciDetail.Events = append(ciDetail.Events, Event{newAttributeValue(event)})
+	}
+
+	return
+}
+
+func newAttributeValue(attribute v2.CiDetailAttribute) AttributeValue {
+	if attribute.AttributeTypeName == ATTRIBUTE_TYPE_NAME_PASSWORD || redact.IsSensitiveKey(attribute.Name) {
+		redact.AddSensitiveValues(attribute.ValueText.String)
+	}
+
+	return AttributeValue{
+		CiAttributeId:     attribute.CiAttributeID.Int(),
+		AttributeId:       attribute.ID.Int(),
+		Name:              attribute.Name,
+		Description:       attribute.Description,
+		AttributeTypeName: attribute.AttributeTypeName,
+		AttributeGroupId:  attribute.AttributeGroupID.Int(),
+		AttributeGroup:    attribute.AttributeGroup,
+		OrderNumber:       attribute.OrderNumber.Int(),
+		HistoryId:         attribute.HistoryID.Int(),
+		Initial:           attribute.Initial.Bool(),
+		ValueText:         attribute.ValueText,
+		ValueDate:         attribute.ValueDate.Time,
+		ValueCi:           attribute.ValueCi.Int(),
+		ValueDefault:      attribute.ValueDefault,
+		ValueNote:         attribute.ValueNote.String,
+		Writable:          attribute.PermissionWrite.Bool(),
+	}
+}
+
+func sortAttributeValues(attributes []AttributeValue) {
+	sort.Slice(attributes, func(i, j int) bool {
+		if attributes[i].OrderNumber != attributes[j].OrderNumber {
+			return attributes[i].OrderNumber < attributes[j].OrderNumber
+		}
+		if attributes[i].AttributeId != attributes[j].AttributeId {
+			return attributes[i].AttributeId < attributes[j].AttributeId
+		}
+		return attributes[i].CiAttributeId < attributes[j].CiAttributeId
+	})
+}
@@ -0,0 +1,120 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_GetCiDetail(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{{
+		RequestString: "GET##/apiV2/ci?id=42##",
+		ReturnString: `{
+    "success": true,
+    "message": "success",
+    "data": {
+        "data": {
+            "ci": {"id": "42", "ci_type_id": "12", "icon": "", "history_id": "1001", "valid_from": "2019-11-27 15:53:32", "created_at": "2019-11-27 15:53:32", "updated_at": null},
+            "ciType": {"id": "12", "name": "emp_germany_berlin", "description": "Berlin", "parent_ci_type_id": "11", "is_ci_attach": "1", "is_event_enabled": "0", "is_active": "1"},
+            "projectList": [
+                {"id": "831", "name": "springfield", "description": "Springfield", "ci_project_valid_from": "2019-11-27 15:53:32"}
+            ],
+            "attributeList": {
+                "7": {
+                    "id": "7", "name": "contact", "description": "Contact", "columns": 1, "readCount": 1, "writeCount": 0,
+                    "attributes": {
+                        "3359": [
+                            {"id": "3359", "name": "emp_email_address", "attribute_group_id": "7", "order_number": "1", "ciAttributeId": "901", "value_text": "cornelia.blank@example.com", "permission_write": "0"}
+                        ],
+                        "3363": [
+                            {"id": "3363", "name": "emp_contact_type", "attribute_group_id": "7", "order_number": "2", "ciAttributeId": "907", "value_text": null, "value_default": "e-mail", "permission_write": "0"}
+                        ]
+                    }
+                },
+                "5": {
+                    "id": "5", "name": "general", "description": "General", "columns": 2, "readCount": 3, "writeCount": 3,
+                    "attributes": {
+                        "3358": [
+                            {"id": "3358", "name": "emp_lastname", "attribute_group_id": "5", "order_number": "2", "ciAttributeId": "903", "value_text": "Blank", "permission_write": "1"}
+                        ],
+                        "3357": [
+                            {"id": "3357", "name": "emp_firstname", "attribute_group_id": "5", "order_number": "1", "ciAttributeId": "902", "value_text": "Cornelia", "permission_write": "1"}
+                        ],
+                        "3362": [
+                            {"id": "3362", "name": "emp_manager", "attribute_group_id": "5", "order_number": "3", "ciAttributeId": "905", "value_text": null, "value_ci": "17", "permission_write": "1"},
+                            {"id": "3362", "name": "emp_manager", "attribute_group_id": "5", "order_number": "3", "ciAttributeId": "904", "value_text": null, "value_ci": "16", "permission_write": "1"}
+                        ]
+                    }
+                }
+            },
+            "breadcrumbs": [
+                {"id": "11", "name": "emp", "description": null, "crumbType": "ci_type"},
+                {"id": "12", "name": "emp_germany_berlin", "description": "Berlin", "crumbType": "ci_type"}
+            ],
+            "events": [
+                {"id": "3400", "name": "emp_onboarding", "ciAttributeId": "906", "value_date": "2019-12-01 00:00:00"}
+            ]
+        }
+    }
+}`,
+	}})
+
+	ciDetail, err := cmdb.GetCiDetail(42)
+	if err != nil {
+		t.Fatalf("GetCiDetail() error = %v", err)
+	}
+
+	if ciDetail.Id != 42 || ciDetail.HistoryId != 1001 || ciDetail.CreatedAt.IsZero() || !ciDetail.UpdatedAt.IsZero() {
+		t.Errorf("GetCiDetail() ci = %+v", ciDetail)
+	}
+	if ciDetail.CiType.Id != 12 || ciDetail.CiType.ParentCiTypeId != 11 || !ciDetail.CiType.IsCiAttach || ciDetail.CiType.IsEventEnabled {
+		t.Errorf("GetCiDetail() ci type = %+v", ciDetail.CiType)
+	}
+	if got := ciDetail.ProjectIds(); !reflect.DeepEqual(got, []int{831}) {
+		t.Errorf("ProjectIds() = %v, want [831]", got)
+	}
+
+	var groups []string
+	for _, group := range ciDetail.AttributeGroups {
+		groups = append(groups, group.Name)
+	}
+	if !reflect.DeepEqual(groups, []string{"general", "contact"}) {
+		t.Errorf("GetCiDetail() attribute groups = %v, want [general contact]", groups)
+	}
+
+	var general []int
+	for _, attribute := range ciDetail.AttributesInGroup("general") {
+		general = append(general, attribute.CiAttributeId)
+	}
+	if !reflect.DeepEqual(general, []int{902, 903, 904, 905}) {
+		t.Errorf("AttributesInGroup() = %v, want [902 903 904 905]", general)
+	}
+
+	if value, found := ciDetail.ValueByName("emp_firstname"); !found || value != "Cornelia" {
+		t.Errorf("ValueByName() = %v, %v, want Cornelia, true", value, found)
+	}
+	if value, found := ciDetail.ValueByName("emp_contact_type"); !found || value != "e-mail" {
+		t.Errorf("ValueByName() of default value = %v, %v, want e-mail, true", value, found)
+	}
+	if _, found := ciDetail.ValueByName("emp_not_existing"); found {
+		t.Error("ValueByName() found not existing attribute")
+	}
+	if values := ciDetail.ValuesByName("emp_manager"); !reflect.DeepEqual(values, []string{"16", "17"}) {
+		t.Errorf("ValuesByName() = %v, want [16 17]", values)
+	}
+
+	if !ciDetail.CanWrite("emp_lastname") || ciDetail.CanWrite("emp_email_address") || ciDetail.CanWrite("emp_not_existing") {
+		t.Error("CanWrite() returned wrong permission")
+	}
+	if contact := ciDetail.AttributeGroups[1]; !contact.CanRead() || contact.CanWrite() {
+		t.Errorf("AttributeGroup permissions = %+v", contact)
+	}
+
+	if len(ciDetail.Breadcrumbs) != 2 || ciDetail.Breadcrumbs[0].Description != "" || ciDetail.Breadcrumbs[1].CiTypeId != 12 {
+		t.Errorf("GetCiDetail() breadcrumbs = %+v", ciDetail.Breadcrumbs)
+	}
+	if len(ciDetail.Events) != 1 || ciDetail.Events[0].Value() != "2019-12-01 00:00:00" {
+		t.Errorf("GetCiDetail() events = %+v", ciDetail.Events)
+	}
+}
@@ -0,0 +1,269 @@
+package infocmdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// How attribute values of the duplicate are merged into the survivor.
+type MergeAttributeMode string
+
+const (
+	// Attributes without value on the survivor get the values of the duplicate
+	MERGE_ATTRIBUTES_FILL_EMPTY MergeAttributeMode = "fill_empty"
+	// Attributes with values on the duplicate replace the values of the survivor
+	MERGE_ATTRIBUTES_PREFER_DUPLICATE MergeAttributeMode = "prefer_duplicate"
+	// Values of the duplicate that the survivor doesn't have are added
+	MERGE_ATTRIBUTES_UNION MergeAttributeMode = "union"
+)
+
+type MergeStrategy struct {
+	// Defaults to MERGE_ATTRIBUTES_FILL_EMPTY
+	Attributes MergeAttributeMode
+	// Attributes that are not merged, e.g. identifiers of the imported record
+	SkipAttributes []string
+	// User the duplicate is deleted by
+	UserId int
+	// Message of the history entry of the changes and the deletion, defaults to "merged into <survivor>"
+	HistoryMessage string
+}
+
+type MergeResult struct {
+	SurvivorId  int
+	DuplicateId int
+	// Sorted names of the attributes of the survivor that were changed
+	ChangedAttributes []string
+	// Relations of the duplicate that exist for the survivor now, including relations it already had
+	MovedRelations []Relation
+	// Ids of the projects the survivor was added to
+	AddedProjects []int
+	// Values of unique attributes that could not be written after the duplicate was deleted,
+	// they can be written again with `UpdateCiAttribute(SurvivorId, PendingUniqueUpdates)`
+	PendingUniqueUpdates []v2.UpdateCiAttribute
+}
+
+// MergeCis moves the attribute values, relations and projects of a duplicate onto the survivor and deletes the duplicate.
+//
+// Relations between the two cis are dropped, relations the survivor already has are kept unchanged.
+// Attachments are not merged. The duplicate is only deleted if all changes of the survivor succeeded,
+// a merge that failed before can be run again as changes that were already made are skipped.
+//
+// Values of unique attributes (webservice int_getUniqueAttributes) are held by the duplicate until it is deleted,
+// so they are written afterwards. If that fails, the error is returned and the values are kept in
+// `PendingUniqueUpdates` (they are also contained in the history of the deleted duplicate).
+func (c *Client) MergeCis(survivorId int, duplicateId int, strategy MergeStrategy) (result MergeResult, err error) {
+	result = MergeResult{SurvivorId: survivorId, DuplicateId: duplicateId}
+
+	if strategy.UserId == 0 {
+		return result, errors.New("missing userId")
+	}
+	if survivorId == duplicateId {
+		return result, utilError.FunctionError(fmt.Sprintf("%d - ci can't be merged with itself", survivorId))
+	}
+	switch strategy.Attributes {
+	case "":
+		strategy.Attributes = MERGE_ATTRIBUTES_FILL_EMPTY
+	case MERGE_ATTRIBUTES_FILL_EMPTY, MERGE_ATTRIBUTES_PREFER_DUPLICATE, MERGE_ATTRIBUTES_UNION:
+	default:
+		return result, utilError.FunctionError("invalid attribute merge mode: " + string(strategy.Attributes))
+	}
+	if strategy.HistoryMessage == "" {
+		strategy.HistoryMessage = fmt.Sprintf("merged into %d", survivorId)
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	survivor, err := c.GetCi(survivorId)
+	if err != nil {
+		return
+	}
+	duplicate, err := c.GetCi(duplicateId)
+	if err != nil {
+		return
+	}
+	if survivor.CiTypeID != duplicate.CiTypeID {
+		err = utilError.FunctionError(fmt.Sprintf("%d, %d - cis of different types (%s, %s) can't be merged",
+			survivorId, duplicateId, survivor.CiType, duplicate.CiType))
+		log.Error("Error: ", err)
+		return
+	}
+
+	attributes, err := c.GetMapOfCiAttributes([]int{survivorId, duplicateId})
+	if err != nil {
+		return
+	}
+	relations, err := c.GetCiRelations(duplicateId)
+	if err != nil {
+		return
+	}
+	uniqueNames, err := c.getUniqueAttributeNames()
+	if err != nil {
+		return
+	}
+	unique := map[string]bool{}
+	for _, name := range uniqueNames {
+		unique[name] = true
+	}
+
+	merge, uniqueMerge := mergeCiAttributes(attributes[survivorId], attributes[duplicateId], strategy, unique)
+
+	historyId, err := c.CreateHistory(strategy.UserId, strategy.HistoryMessage)
+	if err != nil {
+		return
+	}
+
+	for _, projectId := range positiveIds(duplicate.ProjectIDs) {
+		if containsInt(survivor.ProjectIDs, projectId) {
+			continue
+		}
+		if err = c.AddCiProjectMapping(survivorId, projectId, historyId); err != nil {
+			return
+		}
+		result.AddedProjects = append(result.AddedProjects, projectId)
+	}
+
+	if len(merge.updates) > 0 {
+		if err = c.UpdateCiAttribute(survivorId, merge.updates); err != nil {
+			return
+		}
+		result.ChangedAttributes = merge.changed
+	}
+
+	for _, relation := range relations {
+		if relation.OtherCiId(duplicateId) == survivorId {
+			continue
+		}
+		if err = c.copyRelation(relation, duplicateId, survivorId); err != nil {
+			return
+		}
+		result.MovedRelations = append(result.MovedRelations, relation)
+	}
+
+	if err = c.DeleteCi(duplicateId, strategy.UserId, strategy.HistoryMessage); err != nil {
+		return
+	}
+
+	if len(uniqueMerge.updates) > 0 {
+		if err = c.UpdateCiAttribute(survivorId, uniqueMerge.updates); err != nil {
+			result.PendingUniqueUpdates = uniqueMerge.updates
+			return
+		}
+		result.ChangedAttributes = append(result.ChangedAttributes, uniqueMerge.changed...)
+		sort.Strings(result.ChangedAttributes)
+	}
+	return
+}
+
+// attributeMerge contains the updates of the survivor and the sorted names of the changed attributes.
+type attributeMerge struct {
+	updates []v2.UpdateCiAttribute
+	changed []string
+}
+
+// mergeCiAttributes returns the updates of the survivor, those of unique attributes separately.
+func mergeCiAttributes(survivor CiAttributes, duplicate CiAttributes, strategy MergeStrategy, unique map[string]bool) (merge attributeMerge, uniqueMerge attributeMerge) {
+	skipped := map[string]bool{}
+	for _, name := range strategy.SkipAttributes {
+		skipped[name] = true
+	}
+
+	survivorAttributes := map[string][]CiAttribute{}
+	for _, attribute := range survivor {
+		if attribute.Value != "" {
+			survivorAttributes[attribute.AttributeName] = append(survivorAttributes[attribute.AttributeName], attribute)
+		}
+	}
+
+	duplicateValues := map[string][]string{}
+	for _, attribute := range duplicate {
+		if skipped[attribute.AttributeName] || attribute.AttributeType == ATTRIBUTE_TYPE_NAME_ATTACHMENT || attribute.Value == "" {
+			continue
+		}
+		duplicateValues[attribute.AttributeName] = append(duplicateValues[attribute.AttributeName], attribute.Value)
+	}
+
+	names := make([]string, 0, len(duplicateValues))
+	for name := range duplicateValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		current := survivorAttributes[name]
+		var deletes []CiAttribute
+		var inserts []string
+
+		switch strategy.Attributes {
+		case MERGE_ATTRIBUTES_FILL_EMPTY:
+			if len(current) == 0 {
+				inserts = duplicateValues[name]
+			}
+		case MERGE_ATTRIBUTES_PREFER_DUPLICATE:
+			if !equalValues(current, duplicateValues[name]) {
+				deletes, inserts = current, duplicateValues[name]
+			}
+		case MERGE_ATTRIBUTES_UNION:
+			for _, value := range duplicateValues[name] {
+				if !containsValue(current, value) {
+					inserts = append(inserts, value)
+				}
+			}
+		}
+
+		if len(deletes) == 0 && len(inserts) == 0 {
+			continue
+		}
+		target := &merge
+		if unique[name] {
+			target = &uniqueMerge
+		}
+		target.changed = append(target.changed, name)
+		for _, attribute := range deletes {
+			target.updates = append(target.updates, v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_DELETE, Name: name, CiAttributeID: attribute.CiAttributeID.Int()})
+		}
+		for _, value := range inserts {
+			target.updates = append(target.updates, v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_INSERT, Name: name, Value: value})
+		}
+	}
+
+	return
+}
+
+func containsValue(attributes []CiAttribute, value string) bool {
+	for _, attribute := range attributes {
+		if attribute.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares the values independent of their order.
+func equalValues(attributes []CiAttribute, values []string) bool {
+	if len(attributes) != len(values) {
+		return false
+	}
+
+	current := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		current[i] = attribute.Value
+	}
+	desired := append([]string{}, values...)
+	sort.Strings(current)
+	sort.Strings(desired)
+
+	for i := range current {
+		if current[i] != desired[i] {
+			return false
+		}
+	}
+	return true
+}
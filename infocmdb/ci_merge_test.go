@@ -0,0 +1,248 @@
+package infocmdb
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_MergeCis(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"436","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"437"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"437","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield,shelbyville","project_id":"33,34"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"438"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"438","ci_type_id":"13","ci_type":"server","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"436, 437"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"436","ci_attribute_id":"1","attribute_name":"emp_firstname","attribute_type":"input","value":"Ralph"},
+				{"ci_id":"437","ci_attribute_id":"3","attribute_name":"emp_firstname","attribute_type":"input","value":"Ralf"},
+				{"ci_id":"437","ci_attribute_id":"5","attribute_name":"emp_email","attribute_type":"input","value":"ralph@springfield.com"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"437"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"10","ci_id_1":"437","ci_id_2":"500","direction":"4","ci_relation_type_id":"30","ci_relation_type_name":"depends_on"},` +
+				`{"id":"11","ci_id_1":"436","ci_id_2":"437","direction":"4","ci_relation_type_id":"30","ci_relation_type_name":"depends_on"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getUniqueAttributes##{"query":{"params":{}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"emp_email"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"merged into 436"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"1002"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"436","argv2":"34","argv3":"1002"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/436##{"ci":{"attributes":[{"mode":"insert","name":"emp_email","value":"ralph@springfield.com","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"436","argv2":"500","argv3":"30"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelationWithAttributes##` +
+				`{"query":{"params":{"argv1":"436","argv2":"500","argv3":"30","argv4":"4","argv5":"0","argv6":"","argv7":""}}}`,
+			ReturnString: emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCi##{"query":{"params":{"argv1":"437","argv2":"7","argv3":"merged into 436"}}}`,
+			ReturnString:  emptyResult,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "depends_on", 30)
+
+	tests := []struct {
+		name        string
+		duplicateId int
+		strategy    MergeStrategy
+		want        MergeResult
+		wantErr     bool
+	}{
+		{
+			name:        "merge",
+			duplicateId: 437,
+			strategy:    MergeStrategy{UserId: 7},
+			want: MergeResult{
+				SurvivorId:        436,
+				DuplicateId:       437,
+				ChangedAttributes: []string{"emp_email"},
+				MovedRelations: []Relation{
+					{Id: 10, CiId1: 437, CiId2: 500, Direction: v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL, RelationTypeId: 30, RelationTypeName: "depends_on"},
+				},
+				AddedProjects: []int{34},
+			},
+		},
+		{
+			name:        "different ci types",
+			duplicateId: 438,
+			strategy:    MergeStrategy{UserId: 7},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid mode",
+			duplicateId: 437,
+			strategy:    MergeStrategy{UserId: 7, Attributes: "newest"},
+			wantErr:     true,
+		},
+		{
+			name:        "missing user",
+			duplicateId: 437,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.MergeCis(436, tt.duplicateId, tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MergeCis() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeCis() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_MergeCis_failedUniqueAttributes(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"440"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"440","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"441"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"441","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"440, 441"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"441","ci_attribute_id":"8","attribute_name":"emp_email","attribute_type":"input","value":"lisa@springfield.com"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"441"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getUniqueAttributes##{"query":{"params":{}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"emp_email"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"merged into 440"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"1003"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCi##{"query":{"params":{"argv1":"441","argv2":"7","argv3":"merged into 440"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/440##{"ci":{"attributes":[{"mode":"insert","name":"emp_email","value":"lisa@springfield.com","ciAttributeId":0,"uploadId":""}]}}`,
+			StatusCode:    http.StatusBadRequest,
+		},
+	})
+
+	got, err := cmdb.MergeCis(440, 441, MergeStrategy{UserId: 7})
+	if err == nil {
+		t.Fatalf("MergeCis() error = nil, want error of the unique attribute update")
+	}
+
+	want := MergeResult{
+		SurvivorId:  440,
+		DuplicateId: 441,
+		PendingUniqueUpdates: []v2.UpdateCiAttribute{
+			{Mode: v2.UPDATE_MODE_INSERT, Name: "emp_email", Value: "lisa@springfield.com"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeCis() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_mergeCiAttributes(t *testing.T) {
+	attribute := func(ciAttributeId int, name string, value string) CiAttribute {
+		return CiAttribute{CiAttributeID: v2.FlexInt(ciAttributeId), AttributeName: name, AttributeType: "input", Value: value}
+	}
+	survivor := CiAttributes{
+		attribute(1, "emp_firstname", "Ralph"),
+		attribute(2, "emp_phone", "0664 1"),
+		attribute(6, "emp_email", ""),
+	}
+	duplicate := CiAttributes{
+		attribute(3, "emp_firstname", "Ralf"),
+		attribute(4, "emp_phone", "0664 1"),
+		attribute(5, "emp_email", "ralph@springfield.com"),
+		attribute(7, "emp_staff_number", "91655"),
+	}
+	insert := func(name string, value string) v2.UpdateCiAttribute {
+		return v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_INSERT, Name: name, Value: value}
+	}
+
+	unique := map[string]bool{"emp_email": true}
+	wantUnique := attributeMerge{
+		updates: []v2.UpdateCiAttribute{insert("emp_email", "ralph@springfield.com")},
+		changed: []string{"emp_email"},
+	}
+
+	tests := []struct {
+		name string
+		mode MergeAttributeMode
+		want attributeMerge
+	}{
+		{
+			name: "fill empty",
+			mode: MERGE_ATTRIBUTES_FILL_EMPTY,
+		},
+		{
+			name: "prefer duplicate",
+			mode: MERGE_ATTRIBUTES_PREFER_DUPLICATE,
+			want: attributeMerge{
+				updates: []v2.UpdateCiAttribute{
+					{Mode: v2.UPDATE_MODE_DELETE, Name: "emp_firstname", CiAttributeID: 1},
+					insert("emp_firstname", "Ralf"),
+				},
+				changed: []string{"emp_firstname"},
+			},
+		},
+		{
+			name: "union",
+			mode: MERGE_ATTRIBUTES_UNION,
+			want: attributeMerge{
+				updates: []v2.UpdateCiAttribute{insert("emp_firstname", "Ralf")},
+				changed: []string{"emp_firstname"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merge, uniqueMerge := mergeCiAttributes(survivor, duplicate, MergeStrategy{Attributes: tt.mode, SkipAttributes: []string{"emp_staff_number"}}, unique)
+			if !reflect.DeepEqual(merge, tt.want) {
+				t.Errorf("mergeCiAttributes() merge = %+v, want %+v", merge, tt.want)
+			}
+			if !reflect.DeepEqual(uniqueMerge, wantUnique) {
+				t.Errorf("mergeCiAttributes() unique merge = %+v, want %+v", uniqueMerge, wantUnique)
+			}
+		})
+	}
+}
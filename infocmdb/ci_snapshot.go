@@ -0,0 +1,203 @@
+package infocmdb
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// CiSnapshot contains the attribute values of a ci as they were at a point in time.
+type CiSnapshot struct {
+	CiId       int
+	At         time.Time
+	Attributes CiAttributes
+}
+
+// CiDiff contains the attribute values that changed between two points in time.
+type CiDiff struct {
+	CiId    int
+	From    time.Time
+	To      time.Time
+	Changes []CiAttributeChange
+}
+
+var errSnapshotZeroTime = errors.New("point in time is zero")
+
+// GetCiAt returns the attribute values of the ci at the given time.
+//
+// The values are reconstructed from the current values (`GetCiAttributes`) by rolling back the changes of
+// the history entries after the given time (`GetCiHistory`), newest first. Changed values keep their ci attribute,
+// values that were removed since then are restored without ci attribute id. `ModifiedAt` of rolled back values
+// is zero. A ci that didn't exist at that time (all history entries are newer) has no attributes.
+func (c *Client) GetCiAt(ciId int, at time.Time) (snapshot CiSnapshot, err error) {
+	snapshot = CiSnapshot{CiId: ciId, At: at, Attributes: CiAttributes{}}
+	if at.IsZero() {
+		return snapshot, utilError.FunctionError(strconv.Itoa(ciId) + " - " + errSnapshotZeroTime.Error())
+	}
+
+	current, err := c.GetCiAttributes(ciId)
+	if err != nil {
+		return
+	}
+	history, err := c.GetCiHistory(ciId)
+	if err != nil {
+		return
+	}
+
+	attributes := append(CiAttributes{}, current...)
+	existed := len(history) == 0
+	for _, entry := range history {
+		if !entry.Timestamp.After(at) {
+			existed = true
+			break
+		}
+		for _, change := range entry.Changes {
+			attributes = rollbackCiAttributeChange(ciId, attributes, change)
+		}
+	}
+
+	if existed {
+		snapshot.Attributes = attributes
+	}
+	return
+}
+
+// rollbackCiAttributeChange returns the attributes as they were before the change.
+func rollbackCiAttributeChange(ciId int, attributes CiAttributes, change CiAttributeChange) CiAttributes {
+	index := -1
+	if change.NewValue != "" {
+		for i, attribute := range attributes {
+			if attribute.AttributeID.Int() != change.AttributeId {
+				continue
+			}
+			if attribute.Value == change.NewValue {
+				index = i
+				break
+			}
+			if index == -1 {
+				index = i
+			}
+		}
+	}
+
+	switch {
+	case change.NewValue == "" && change.OldValue == "":
+		return attributes
+	case change.NewValue == "":
+		return append(attributes, CiAttribute{
+			CiID:          v2.FlexInt(ciId),
+			AttributeID:   v2.FlexInt(change.AttributeId),
+			AttributeName: change.Attribute,
+			AttributeType: change.AttributeTypeName,
+			Value:         change.OldValue,
+		})
+	case index == -1:
+		log.Debugf("Ci %d: value of %s changed in the history not found", ciId, change.Attribute)
+		return attributes
+	case change.OldValue == "":
+		return append(attributes[:index:index], attributes[index+1:]...)
+	}
+
+	attributes[index].Value = change.OldValue
+	attributes[index].ModifiedAt = v2.FlexTime{}
+	return attributes
+}
+
+// GetAndBindCiAt binds the attribute values of the ci at the given time like `GetAndBindCi`.
+func (c *Client) GetAndBindCiAt(ciId int, at time.Time, out interface{}) (err error) {
+	snapshot, err := c.GetCiAt(ciId, at)
+	if err != nil {
+		return
+	}
+
+	return bindCi(ciId, snapshot.Attributes, out)
+}
+
+// DiffCi returns the attribute values that were added, removed or changed between from and to.
+//
+// Values are compared by their ci attribute (restored values without ci attribute by attribute and value),
+// a value that was removed and added again is reported twice.
+// Changes are sorted by attribute name.
+func (c *Client) DiffCi(ciId int, from time.Time, to time.Time) (diff CiDiff, err error) {
+	diff = CiDiff{CiId: ciId, From: from, To: to, Changes: []CiAttributeChange{}}
+
+	before, err := c.GetCiAt(ciId, from)
+	if err != nil {
+		return
+	}
+	after, err := c.GetCiAt(ciId, to)
+	if err != nil {
+		return
+	}
+
+	diff.Changes = diffCiAttributes(before.Attributes, after.Attributes)
+	return
+}
+
+func diffCiAttributes(before CiAttributes, after CiAttributes) (changes []CiAttributeChange) {
+	changes = []CiAttributeChange{}
+
+	remaining := map[string]CiAttribute{}
+	for _, attribute := range after {
+		remaining[ciAttributeKey(attribute)] = attribute
+	}
+
+	newChange := func(attribute CiAttribute) CiAttributeChange {
+		return CiAttributeChange{
+			AttributeId:       attribute.AttributeID.Int(),
+			Attribute:         attribute.AttributeName,
+			AttributeTypeName: attribute.AttributeType,
+		}
+	}
+
+	for _, old := range before {
+		change := newChange(old)
+		change.OldValue = old.Value
+
+		current, found := remaining[ciAttributeKey(old)]
+		if found {
+			delete(remaining, ciAttributeKey(old))
+			if current.Value == old.Value {
+				continue
+			}
+			change.NewValue = current.Value
+		}
+		changes = append(changes, change)
+	}
+
+	for _, attribute := range after {
+		if _, added := remaining[ciAttributeKey(attribute)]; !added {
+			continue
+		}
+		change := newChange(attribute)
+		change.NewValue = attribute.Value
+		changes = append(changes, change)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Attribute < changes[j].Attribute })
+	return
+}
+
+// ciAttributeKey identifies the value by its ci attribute, restored values by attribute and value.
+func ciAttributeKey(attribute CiAttribute) string {
+	if attribute.CiAttributeID.Int() != 0 {
+		return strconv.Itoa(attribute.CiAttributeID.Int())
+	}
+	return strconv.Itoa(attribute.AttributeID.Int()) + "=" + attribute.Value
+}
+
+// ValuesByName returns the values of the attribute at the time of the snapshot.
+func (s CiSnapshot) ValuesByName(attributeName string) (values []string) {
+	for _, attribute := range s.Attributes {
+		if attribute.AttributeName == attributeName {
+			values = append(values, attribute.Value)
+		}
+	}
+	return
+}
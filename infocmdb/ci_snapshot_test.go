@@ -0,0 +1,115 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+// newSnapshotTestClient mocks ci 42 created on 2019-06-01, its ip replaced on 2019-08-01 and hostname changed twice.
+func newSnapshotTestClient() *Client {
+	return newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"42"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"42","ci_attribute_id":"100","attribute_id":"10","attribute_name":"hostname","attribute_type":"input","value":"srv-03"},
+				{"ci_id":"42","ci_attribute_id":"102","attribute_id":"12","attribute_name":"dns","attribute_type":"input","value":"srv.local"},
+				{"ci_id":"42","ci_attribute_id":"103","attribute_id":"11","attribute_name":"ip","attribute_type":"input","value":"10.0.0.2"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiHistory##{"query":{"params":{"argv1":"42"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"history_id":"900","datestamp":"2019-06-01 08:00:00","user_id":"7","note":"ci created",
+				 "attribute_id":"10","attribute_name":"hostname","attribute_type":"input","old_value":null,"new_value":"srv-01"},
+				{"history_id":"900","datestamp":"2019-06-01 08:00:00","user_id":"7","note":"ci created",
+				 "attribute_id":"11","attribute_name":"ip","attribute_type":"input","old_value":null,"new_value":"10.0.0.1"},
+				{"history_id":"900","datestamp":"2019-06-01 08:00:00","user_id":"7","note":"ci created",
+				 "attribute_id":"12","attribute_name":"dns","attribute_type":"input","old_value":null,"new_value":"srv.local"},
+				{"history_id":"1002","datestamp":"2019-08-01 08:00:00","user_id":"7","note":"migration",
+				 "attribute_id":"10","attribute_name":"hostname","attribute_type":"input","old_value":"srv-01","new_value":"srv-02"},
+				{"history_id":"1002","datestamp":"2019-08-01 08:00:00","user_id":"7","note":"migration",
+				 "attribute_id":"11","attribute_name":"ip","attribute_type":"input","old_value":"10.0.0.1","new_value":null},
+				{"history_id":"1002","datestamp":"2019-08-01 08:00:00","user_id":"7","note":"migration",
+				 "attribute_id":"11","attribute_name":"ip","attribute_type":"input","old_value":null,"new_value":"10.0.0.2"},
+				{"history_id":"1003","datestamp":"2019-12-01 08:00:00","user_id":"7","note":"rename",
+				 "attribute_id":"10","attribute_name":"hostname","attribute_type":"input","old_value":"srv-02","new_value":"srv-03"}]}`,
+		},
+	})
+}
+
+func TestClient_GetCiAt(t *testing.T) {
+	cmdb := newSnapshotTestClient()
+
+	tests := []struct {
+		name         string
+		at           time.Time
+		wantHostname []string
+		wantErr      bool
+	}{
+		{"last quarter", time.Date(2019, 7, 1, 0, 0, 0, 0, v2.FlexTimeLocation), []string{"srv-01"}, false},
+		{"now", time.Date(2019, 10, 1, 0, 0, 0, 0, v2.FlexTimeLocation), []string{"srv-02"}, false},
+		{"before creation", time.Date(2019, 5, 1, 0, 0, 0, 0, v2.FlexTimeLocation), nil, false},
+		{"zero time", time.Time{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.GetCiAt(42, tt.at)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCiAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := got.ValuesByName("hostname"); !reflect.DeepEqual(got, tt.wantHostname) {
+				t.Errorf("GetCiAt() hostname = %v, want %v", got, tt.wantHostname)
+			}
+		})
+	}
+}
+
+func TestClient_GetAndBindCiAt(t *testing.T) {
+	cmdb := newSnapshotTestClient()
+
+	type server struct {
+		Id       int    `ci:"id"`
+		Hostname string `attr:"hostname"`
+		Ip       string `attr:"ip"`
+	}
+
+	var got server
+	err := cmdb.GetAndBindCiAt(42, time.Date(2019, 7, 1, 0, 0, 0, 0, v2.FlexTimeLocation), &got)
+	if err != nil {
+		t.Fatalf("GetAndBindCiAt() error = %v", err)
+	}
+
+	want := server{Id: 42, Hostname: "srv-01", Ip: "10.0.0.1"}
+	if got != want {
+		t.Errorf("GetAndBindCiAt() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_DiffCi(t *testing.T) {
+	cmdb := newSnapshotTestClient()
+	from := time.Date(2019, 7, 1, 0, 0, 0, 0, v2.FlexTimeLocation)
+	to := time.Date(2019, 10, 1, 0, 0, 0, 0, v2.FlexTimeLocation)
+
+	got, err := cmdb.DiffCi(42, from, to)
+	if err != nil {
+		t.Fatalf("DiffCi() error = %v", err)
+	}
+
+	want := CiDiff{
+		CiId: 42,
+		From: from,
+		To:   to,
+		Changes: []CiAttributeChange{
+			{AttributeId: 10, Attribute: "hostname", AttributeTypeName: "input", OldValue: "srv-01", NewValue: "srv-02"},
+			{AttributeId: 11, Attribute: "ip", AttributeTypeName: "input", OldValue: "10.0.0.1"},
+			{AttributeId: 11, Attribute: "ip", AttributeTypeName: "input", NewValue: "10.0.0.2"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffCi() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+package infocmdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+var ErrSyncThresholdExceeded = errors.New("too many cis would be removed")
+
+// Action for cis whose key is not part of the synchronized records.
+type SyncMissingAction string
+
+const (
+	SYNC_MISSING_KEEP   SyncMissingAction = "keep"
+	SYNC_MISSING_DELETE SyncMissingAction = "delete"
+	// Set `SyncOptions.FlagAttribute` to `SyncOptions.FlagValue`
+	SYNC_MISSING_FLAG SyncMissingAction = "flag"
+)
+
+type SyncOptions struct {
+	// Only compute the report, nothing is changed
+	DryRun bool
+	// Defaults to SYNC_MISSING_KEEP
+	MissingAction SyncMissingAction
+	FlagAttribute string
+	FlagValue     string
+	// Abort without changes if a larger ratio (0.1 for 10%) of the existing cis would be removed, 0 disables the check
+	MaxRemoveRatio float64
+	// Projects of created cis
+	Projects []string
+	// User for history entries and deletions
+	UserId int
+	// History message of created cis
+	HistoryMessage string
+}
+
+// SyncChange is a ci that is (or would be) created, updated or removed.
+type SyncChange struct {
+	Key  string
+	CiId int
+	// Names of the attributes that are written
+	Attributes []string
+}
+
+type SyncReport struct {
+	DryRun    bool
+	Created   []SyncChange
+	Updated   []SyncChange
+	Removed   []SyncChange
+	Unchanged int
+}
+
+// SyncCiType reconciles the cis of the given type with an external dataset.
+//
+// Each record maps attribute names to values and is identified by the value of keyAttribute.
+// Records without ci are created, cis with differing values are updated (only the differing attributes are written)
+// and cis without record are kept, deleted or flagged according to the options.
+// The report lists the changes, with `DryRun` nothing is changed. Failures of single cis don't stop the
+// synchronization, they are returned together after all records are processed.
+func (c *Client) SyncCiType(ciTypeName string, keyAttribute string, records []map[string]string, opts SyncOptions) (report SyncReport, err error) {
+	if opts.MissingAction == "" {
+		opts.MissingAction = SYNC_MISSING_KEEP
+	}
+	if opts.MissingAction == SYNC_MISSING_FLAG && opts.FlagAttribute == "" {
+		return report, errors.New("missing flag attribute")
+	}
+	report.DryRun = opts.DryRun
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	ciIds, err := c.GetListOfCiIdsOfCiTypeName(ciTypeName)
+	if err != nil {
+		return
+	}
+	existingAttributes, err := c.GetMapOfCiAttributes(ciIds)
+	if err != nil {
+		return
+	}
+
+	existing, err := indexCisByKey(ciTypeName, keyAttribute, ciIds, existingAttributes)
+	if err != nil {
+		return
+	}
+
+	recordsByKey := map[string]map[string]string{}
+	for i, record := range records {
+		key, ok := record[keyAttribute]
+		if !ok || key == "" {
+			return report, utilError.FunctionError(fmt.Sprintf("%s - record %d has no %s", ciTypeName, i, keyAttribute))
+		}
+		if _, duplicate := recordsByKey[key]; duplicate {
+			return report, utilError.FunctionError(fmt.Sprintf("%s - duplicate record %s=%s", ciTypeName, keyAttribute, key))
+		}
+		recordsByKey[key] = record
+	}
+
+	for _, record := range records {
+		key := record[keyAttribute]
+		ciId, found := existing[key]
+		if !found {
+			report.Created = append(report.Created, SyncChange{Key: key, Attributes: sortedNames(record)})
+			continue
+		}
+
+		changed := changedAttributes(existingAttributes[ciId], record)
+		if len(changed) == 0 {
+			report.Unchanged++
+			continue
+		}
+		report.Updated = append(report.Updated, SyncChange{Key: key, CiId: ciId, Attributes: changed})
+	}
+
+	if opts.MissingAction != SYNC_MISSING_KEEP {
+		for _, key := range sortedKeys(existing) {
+			if _, found := recordsByKey[key]; found {
+				continue
+			}
+			ciId := existing[key]
+			change := SyncChange{Key: key, CiId: ciId}
+			if opts.MissingAction == SYNC_MISSING_FLAG {
+				flag := map[string]string{opts.FlagAttribute: opts.FlagValue}
+				if change.Attributes = changedAttributes(existingAttributes[ciId], flag); len(change.Attributes) == 0 {
+					continue
+				}
+			}
+			report.Removed = append(report.Removed, change)
+		}
+	}
+
+	if opts.MaxRemoveRatio > 0 && len(existing) > 0 {
+		if ratio := float64(len(report.Removed)) / float64(len(existing)); ratio > opts.MaxRemoveRatio {
+			err = utilError.FunctionError(fmt.Sprintf("%s - %s: %d of %d", ciTypeName, ErrSyncThresholdExceeded.Error(), len(report.Removed), len(existing)))
+			log.Error("Error: ", err)
+			return
+		}
+	}
+
+	if opts.DryRun {
+		return
+	}
+
+	return report, c.applySync(ciTypeName, &report, recordsByKey, existingAttributes, opts)
+}
+
+func (c *Client) applySync(ciTypeName string, report *SyncReport, records map[string]map[string]string, existingAttributes map[int]CiAttributes, opts SyncOptions) error {
+	var errs utilError.Errors
+
+	for i, change := range report.Created {
+		ciId, err := c.CreateCiWithAttributes(CreateCiRequest{
+			CiType:         ciTypeName,
+			Projects:       opts.Projects,
+			Attributes:     records[change.Key],
+			HistoryMessage: opts.HistoryMessage,
+			UserId:         opts.UserId,
+		})
+		if err != nil {
+			errs = errs.Add(err)
+			continue
+		}
+		report.Created[i].CiId = ciId
+	}
+
+	for _, change := range report.Updated {
+		if _, err := c.updateChangedAttributes(change.CiId, existingAttributes[change.CiId], records[change.Key]); err != nil {
+			errs = errs.Add(err)
+		}
+	}
+
+	for _, change := range report.Removed {
+		var err error
+		switch opts.MissingAction {
+		case SYNC_MISSING_DELETE:
+			err = c.DeleteCi(change.CiId, opts.UserId, "ci removed by synchronization of "+ciTypeName)
+		case SYNC_MISSING_FLAG:
+			err = c.UpdateCiAttribute(change.CiId, []v2.UpdateCiAttribute{
+				{Mode: v2.UPDATE_MODE_SET, Name: opts.FlagAttribute, Value: opts.FlagValue},
+			})
+		}
+		if err != nil {
+			errs = errs.Add(err)
+		}
+	}
+
+	if len(errs) > 0 {
+		log.Error("Error: ", errs)
+		return errs
+	}
+	return nil
+}
+
+// indexCisByKey returns the ci ids by the value of the key attribute, cis without key are skipped.
+func indexCisByKey(ciTypeName string, keyAttribute string, ciIds CiIds, attributes map[int]CiAttributes) (index map[string]int, err error) {
+	index = map[string]int{}
+	for _, ciId := range ciIds {
+		for _, attribute := range attributes[ciId] {
+			if attribute.AttributeName != keyAttribute || attribute.Value == "" {
+				continue
+			}
+			if duplicate, exists := index[attribute.Value]; exists {
+				return nil, utilError.FunctionError(fmt.Sprintf("%s %s=%s - %s: [%d %d]",
+					ciTypeName, keyAttribute, attribute.Value, v2.ErrTooManyResults.Error(), duplicate, ciId))
+			}
+			index[attribute.Value] = ciId
+			break
+		}
+	}
+	return
+}
+
+func sortedKeys(index map[string]int) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns a summary of the report, e.g. for logging dry runs.
+func (r SyncReport) String() string {
+	prefix := ""
+	if r.DryRun {
+		prefix = "dry run: "
+	}
+	return prefix + strconv.Itoa(len(r.Created)) + " created, " + strconv.Itoa(len(r.Updated)) + " updated, " +
+		strconv.Itoa(len(r.Removed)) + " removed, " + strconv.Itoa(r.Unchanged) + " unchanged"
+}
@@ -0,0 +1,156 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func newSyncTestClient(mockings []utilTesting.Mocking) *Client {
+	cmdb := newTestClient(append([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getListOfCiIdsOfCiType##{"query":{"params":{"argv1":"12"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ciid":"436"},{"ciid":"437"},{"ciid":"438"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"436, 437, 438"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"436","attribute_name":"emp_staff_number","attribute_type":"input","value":"91651"},
+				{"ci_id":"436","attribute_name":"emp_lastname","attribute_type":"input","value":"Blank"},
+				{"ci_id":"437","attribute_name":"emp_staff_number","attribute_type":"input","value":"91652"},
+				{"ci_id":"437","attribute_name":"emp_lastname","attribute_type":"input","value":"Wiggum"},
+				{"ci_id":"438","attribute_name":"emp_staff_number","attribute_type":"input","value":"91653"},
+				{"ci_id":"438","attribute_name":"emp_lastname","attribute_type":"input","value":"Simpson"}]}`,
+		},
+	}, mockings...))
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "emp_germany_berlin", 12)
+	cmdb.metadataCache().Set(METADATA_PROJECT, "springfield", 33)
+	return cmdb
+}
+
+var syncTestRecords = []map[string]string{
+	{"emp_staff_number": "91651", "emp_lastname": "Blank-Wiggum"},
+	{"emp_staff_number": "91652", "emp_lastname": "Wiggum"},
+	{"emp_staff_number": "91654", "emp_lastname": "Flanders"},
+}
+
+func TestClient_SyncCiType_dryRun(t *testing.T) {
+	cmdb := newSyncTestClient(nil)
+
+	tests := []struct {
+		name    string
+		opts    SyncOptions
+		want    SyncReport
+		wantErr bool
+	}{
+		{
+			"keep missing",
+			SyncOptions{DryRun: true},
+			SyncReport{
+				DryRun:    true,
+				Created:   []SyncChange{{Key: "91654", Attributes: []string{"emp_lastname", "emp_staff_number"}}},
+				Updated:   []SyncChange{{Key: "91651", CiId: 436, Attributes: []string{"emp_lastname"}}},
+				Unchanged: 1,
+			},
+			false,
+		},
+		{
+			"flag missing",
+			SyncOptions{DryRun: true, MissingAction: SYNC_MISSING_FLAG, FlagAttribute: "emp_lastname", FlagValue: "Simpson"},
+			SyncReport{
+				DryRun:    true,
+				Created:   []SyncChange{{Key: "91654", Attributes: []string{"emp_lastname", "emp_staff_number"}}},
+				Updated:   []SyncChange{{Key: "91651", CiId: 436, Attributes: []string{"emp_lastname"}}},
+				Unchanged: 1,
+			},
+			false,
+		},
+		{
+			"threshold exceeded",
+			SyncOptions{DryRun: true, MissingAction: SYNC_MISSING_DELETE, MaxRemoveRatio: 0.1},
+			SyncReport{
+				DryRun:    true,
+				Created:   []SyncChange{{Key: "91654", Attributes: []string{"emp_lastname", "emp_staff_number"}}},
+				Updated:   []SyncChange{{Key: "91651", CiId: 436, Attributes: []string{"emp_lastname"}}},
+				Removed:   []SyncChange{{Key: "91653", CiId: 438}},
+				Unchanged: 1,
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.SyncCiType("emp_germany_berlin", "emp_staff_number", syncTestRecords, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SyncCiType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SyncCiType() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_SyncCiType(t *testing.T) {
+	cmdb := newSyncTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"439","ci_type_id":"12","icon":"","history_id":"59529050"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"439","argv2":"33","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":null}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/439##{"ci":{"attributes":[{"mode":"set","name":"emp_lastname","value":"Flanders","ciAttributeId":0,"uploadId":""},{"mode":"set","name":"emp_staff_number","value":"91654","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/436##{"ci":{"attributes":[{"mode":"set","name":"emp_lastname","value":"Blank-Wiggum","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCi##{"query":{"params":{"argv1":"438","argv2":"7","argv3":"ci removed by synchronization of emp_germany_berlin"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	got, err := cmdb.SyncCiType("emp_germany_berlin", "emp_staff_number", syncTestRecords, SyncOptions{
+		MissingAction: SYNC_MISSING_DELETE,
+		Projects:      []string{"springfield"},
+		UserId:        7,
+	})
+	if err != nil {
+		t.Fatalf("SyncCiType() error = %v", err)
+	}
+
+	want := SyncReport{
+		Created:   []SyncChange{{Key: "91654", CiId: 439, Attributes: []string{"emp_lastname", "emp_staff_number"}}},
+		Updated:   []SyncChange{{Key: "91651", CiId: 436, Attributes: []string{"emp_lastname"}}},
+		Removed:   []SyncChange{{Key: "91653", CiId: 438}},
+		Unchanged: 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SyncCiType() got = %+v, want %+v", got, want)
+	}
+	if got.String() != "1 created, 1 updated, 1 removed, 1 unchanged" {
+		t.Errorf("String() = %v", got.String())
+	}
+}
+
+func TestClient_SyncCiType_invalidRecords(t *testing.T) {
+	cmdb := newSyncTestClient(nil)
+
+	for name, records := range map[string][]map[string]string{
+		"missing key":   {{"emp_lastname": "Flanders"}},
+		"duplicate key": {{"emp_staff_number": "91654"}, {"emp_staff_number": "91654"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := cmdb.SyncCiType("emp_germany_berlin", "emp_staff_number", records, SyncOptions{}); err == nil {
+				t.Error("SyncCiType() error = nil, want error")
+			}
+		})
+	}
+}
@@ -13,49 +13,39 @@ import (
 )
 
 type getCiTypeIdByCiTypeName struct {
-	Data []responseId `json:"data"`
+	Name string `argv:"1"`
 }
 
+func (getCiTypeIdByCiTypeName) QueryName() string { return "int_getCiTypeIdByCiTypeName" }
+
 func (c *Client) GetCiTypeIdByCiTypeName(name string) (r int, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
 
-	cacheKey := "GetCiTypeIdByCiTypeName_" + name
-	cached, found := c.v1.Cache.Get(cacheKey)
-	if found {
-		return cached.(int), nil
+	if cached, found := c.metadataCache().Get(METADATA_CI_TYPE, name); found {
+		return cached, nil
 	}
 
-	params := map[string]string{
-		"argv1": name,
-	}
-
-	response := getCiTypeIdByCiTypeName{}
-	err = c.v2.Query("int_getCiTypeIdByCiTypeName", &response, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := responseId{}
+	if err = c.queryOne(getCiTypeIdByCiTypeName{Name: name}, &row, name); err != nil {
 		return
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(name + " - " + v2.ErrNoResult.Error())
-	case 1:
-		r = response.Data[0].Id
-		c.v1.Cache.Set(cacheKey, r, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(name + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	r = row.Id.Int()
+	c.metadataCache().Set(METADATA_CI_TYPE, name, r)
 	return
 }
 
-type getCiTypeName struct {
-	Data []struct {
-		Name string `json:"name"`
-	} `json:"data"`
+type getCiTypeOfCi struct {
+	CiId   int    `argv:"1"`
+	Column string `argv:"2"`
+}
+
+func (getCiTypeOfCi) QueryName() string { return "int_getCiTypeOfCi" }
+
+type ciTypeNameRow struct {
+	Name string `json:"name"`
 }
 
 func (c *Client) GetCiTypeName(ciId int) (ciTypeName string, err error) {
@@ -71,28 +61,13 @@ func (c *Client) GetCiTypeName(ciId int) (ciTypeName string, err error) {
 		return cached.(string), nil
 	}
 
-	params := map[string]string{
-		"argv1": ciIdString,
-		"argv2": "name",
-	}
-	response := getCiTypeName{}
-	err = c.v2.Query("int_getCiTypeOfCi", &response, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := ciTypeNameRow{}
+	if err = c.queryOne(getCiTypeOfCi{CiId: ciId, Column: "name"}, &row, ciIdString); err != nil {
 		return
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(ciIdString + " - " + v2.ErrNoResult.Error())
-	case 1:
-		ciTypeName = response.Data[0].Name
-		c.v1.Cache.Set(cacheKey, ciTypeName, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(ciIdString + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	ciTypeName = row.Name
+	c.v1.Cache.Set(cacheKey, ciTypeName, utilCache.DefaultExpiration)
 	return
 }
 
@@ -121,13 +96,18 @@ func (c *Client) SetTypeOfCi(ciId int, ciType string) (err error) {
 		return err
 	}
 
+	return c.setTypeOfCi(ciId, ciTypeId, ciType, 0)
+}
+
+// setTypeOfCi changes the type, the change is grouped under the history entry if historyId isn't 0.
+func (c *Client) setTypeOfCi(ciId int, ciTypeId int, ciType string, historyId int) (err error) {
 	ciTypeIdString := strconv.Itoa(ciTypeId)
 	ciIdString := strconv.Itoa(ciId)
 
 	params := map[string]string{
 		"argv1": ciIdString,
 		"argv2": ciTypeIdString,
-		"argv3": "0",
+		"argv3": strconv.Itoa(historyId),
 	}
 
 	response := respSetTypeOfCi{}
@@ -142,6 +122,8 @@ func (c *Client) SetTypeOfCi(ciId int, ciType string) (err error) {
 		return errors.New("couldn't change ci type to: " + ciType + " for ciid: " + ciIdString + " ,error: " + response.Message)
 	}
 
+	c.v1.Cache.Delete("GetCiTypeName_" + ciIdString)
+
 	return
 }
 
@@ -272,7 +254,8 @@ func (c *Client) CreateCiType(typeParams *CiTypeParams) (typeId int, err error)
 		case 0:
 			err = utilError.FunctionError(typeParams.Name + " - " + v2.ErrNoResult.Error())
 		case 1:
-			typeId = response.Data[0].Id
+			typeId = response.Data[0].Id.Int()
+			c.InvalidateMetadata(METADATA_CI_TYPE)
 		default:
 			err = utilError.FunctionError(typeParams.Name + " - " + v2.ErrTooManyResults.Error())
 		}
@@ -0,0 +1,238 @@
+package infocmdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+var ErrCiTypeChangeDataLoss = errors.New("attribute values would be lost")
+
+type ChangeCiTypeOptions struct {
+	// Only compute the report, nothing is changed
+	DryRun bool
+	// Change the type even if values of attributes that are not allowed for the new type are lost
+	AllowDataLoss bool
+	// User the history entry is created for
+	UserId int
+	// Message of the history entry the changes are grouped under, none is created if empty
+	HistoryMessage string
+}
+
+// AttributeMigration moves the values of an attribute to another attribute of the new ci type.
+type AttributeMigration struct {
+	From   string
+	To     string
+	Values []string
+}
+
+type CiTypeChangeReport struct {
+	DryRun  bool
+	CiId    int
+	OldType string
+	NewType string
+	// Sorted names of the attributes with values that are allowed for the new type
+	Kept     []string
+	Migrated []AttributeMigration
+	// Values of attributes that are neither allowed for the new type nor mapped
+	Lost CiAttributes
+}
+
+// ChangeCiType changes the type of the ci and moves attribute values according to the mapping.
+//
+// The mapping renames attributes (old name to new name) that are not allowed for the new type, the target
+// attributes must be allowed for the new type, must not have values yet and can neither be the target of
+// another attribute nor be mapped themselves. Values of other attributes that are not allowed for the new type
+// are reported as lost, the type is only changed if `AllowDataLoss` is set.
+// With `DryRun` only the report is returned. If moving the values fails, the old type is restored.
+func (c *Client) ChangeCiType(ciId int, newType string, mapping map[string]string, opts ChangeCiTypeOptions) (report CiTypeChangeReport, err error) {
+	report = CiTypeChangeReport{DryRun: opts.DryRun, CiId: ciId, NewType: newType, Lost: CiAttributes{}}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	if report.OldType, err = c.GetCiTypeName(ciId); err != nil {
+		return
+	}
+	if report.OldType == newType {
+		return report, errors.New("the requested ci type is already set: " + newType)
+	}
+
+	oldTypeId, err := c.GetCiTypeIdByCiTypeName(report.OldType)
+	if err != nil {
+		return
+	}
+	newTypeId, err := c.GetCiTypeIdByCiTypeName(newType)
+	if err != nil {
+		return
+	}
+	allowed, err := c.getAttributeNamesOfCiType(newTypeId)
+	if err != nil {
+		return
+	}
+	attributes, err := c.GetCiAttributes(ciId)
+	if err != nil {
+		return
+	}
+
+	updates, err := planCiTypeChange(&report, attributes, allowed, mapping)
+	if err != nil {
+		log.Error("Error: ", err)
+		return
+	}
+
+	if opts.DryRun {
+		return
+	}
+
+	if len(report.Lost) > 0 && !opts.AllowDataLoss {
+		lost := make([]string, len(report.Lost))
+		for i, attribute := range report.Lost {
+			lost[i] = attribute.AttributeName
+		}
+		err = utilError.FunctionError(fmt.Sprintf("%d, %s - %s: %v", ciId, newType, ErrCiTypeChangeDataLoss.Error(), lost))
+		log.Error("Error: ", err)
+		return
+	}
+
+	historyId := 0
+	if opts.HistoryMessage != "" {
+		if historyId, err = c.CreateHistory(opts.UserId, opts.HistoryMessage); err != nil {
+			return
+		}
+	}
+
+	if err = c.setTypeOfCi(ciId, newTypeId, newType, historyId); err != nil {
+		return
+	}
+
+	if len(updates) > 0 {
+		if err = c.UpdateCiAttribute(ciId, updates); err != nil {
+			return report, c.restoreCiType(ciId, oldTypeId, report.OldType, historyId, err)
+		}
+	}
+
+	return
+}
+
+// planCiTypeChange fills the report and returns the updates that move the mapped values.
+func planCiTypeChange(report *CiTypeChangeReport, attributes CiAttributes, allowed []string, mapping map[string]string) (updates []v2.UpdateCiAttribute, err error) {
+	isAllowed := map[string]bool{}
+	for _, name := range allowed {
+		isAllowed[name] = true
+	}
+
+	mappedTo := map[string]string{}
+	for _, from := range sortedNames(mapping) {
+		to := mapping[from]
+		if !isAllowed[to] {
+			return nil, utilError.FunctionError(fmt.Sprintf("%s - mapped attribute %s is not allowed for %s", from, to, report.NewType))
+		}
+		if other, duplicate := mappedTo[to]; duplicate {
+			return nil, utilError.FunctionError(fmt.Sprintf("%s - attribute %s is already mapped from %s", from, to, other))
+		}
+		if _, chained := mapping[to]; chained {
+			return nil, utilError.FunctionError(fmt.Sprintf("%s - mapped attribute %s is mapped itself", from, to))
+		}
+		mappedTo[to] = from
+	}
+
+	kept := map[string]bool{}
+	migrated := map[string][]CiAttribute{}
+	for _, attribute := range attributes {
+		if attribute.Value == "" {
+			continue
+		}
+		if _, mapped := mapping[attribute.AttributeName]; mapped {
+			migrated[attribute.AttributeName] = append(migrated[attribute.AttributeName], attribute)
+			continue
+		}
+		if isAllowed[attribute.AttributeName] {
+			kept[attribute.AttributeName] = true
+			continue
+		}
+		report.Lost = append(report.Lost, attribute)
+	}
+
+	for name := range kept {
+		report.Kept = append(report.Kept, name)
+	}
+	sort.Strings(report.Kept)
+
+	for _, from := range sortedNames(mapping) {
+		to := mapping[from]
+		if len(migrated[from]) == 0 {
+			continue
+		}
+		if kept[to] {
+			return nil, utilError.FunctionError(fmt.Sprintf("%s - mapped attribute %s already has a value", from, to))
+		}
+
+		migration := AttributeMigration{From: from, To: to}
+		for _, attribute := range migrated[from] {
+			migration.Values = append(migration.Values, attribute.Value)
+			updates = append(updates,
+				v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_INSERT, Name: to, Value: attribute.Value},
+				v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_DELETE, Name: from, CiAttributeID: attribute.CiAttributeID.Int()},
+			)
+		}
+		report.Migrated = append(report.Migrated, migration)
+	}
+
+	return
+}
+
+// restoreCiType changes the type back after the values couldn't be moved, the returned error contains the cause and a failed restore.
+func (c *Client) restoreCiType(ciId int, ciTypeId int, ciType string, historyId int, cause error) error {
+	log.Warnf("Moving attribute values of ci %d failed, restoring type %s: %v", ciId, ciType, cause)
+
+	errs := utilError.Errors{}.Add(utilError.FunctionError(strconv.Itoa(ciId) + " - " + cause.Error()))
+	if err := c.setTypeOfCi(ciId, ciTypeId, ciType, historyId); err != nil {
+		errs = errs.Add(utilError.FunctionError(strconv.Itoa(ciId) + " - restoring type failed: " + err.Error()))
+	}
+
+	log.Error("Error: ", errs)
+	return errs
+}
+
+type getAttributeNamesOfCiType struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+func (c *Client) getAttributeNamesOfCiType(ciTypeId int) (names []string, err error) {
+	params := map[string]string{
+		"argv1": strconv.Itoa(ciTypeId),
+	}
+
+	jsonRet := getAttributeNamesOfCiType{}
+	err = c.v2.Query("int_getAttributesOfCiType", &jsonRet, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	for _, row := range jsonRet.Data {
+		names = append(names, row.Name)
+	}
+	return
+}
+
+// String returns a summary of the report, e.g. for logging dry runs.
+func (r CiTypeChangeReport) String() string {
+	prefix := ""
+	if r.DryRun {
+		prefix = "dry run: "
+	}
+	return prefix + strconv.Itoa(r.CiId) + " " + r.OldType + " -> " + r.NewType + ": " + strconv.Itoa(len(r.Kept)) + " kept, " +
+		strconv.Itoa(len(r.Migrated)) + " migrated, " + strconv.Itoa(len(r.Lost)) + " lost"
+}
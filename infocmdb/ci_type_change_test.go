@@ -0,0 +1,142 @@
+package infocmdb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_ChangeCiType(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiTypeOfCi##{"query":{"params":{"argv1":"436","argv2":"name"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"emp_germany_berlin"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getAttributesOfCiType##{"query":{"params":{"argv1":"14"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"name":"emp_firstname"},{"name":"emp_lastname"},{"name":"emp_personnel_number"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"436","ci_attribute_id":"1","attribute_name":"emp_firstname","attribute_type":"input","value":"Ralph"},
+				{"ci_id":"436","ci_attribute_id":"2","attribute_name":"emp_staff_number","attribute_type":"input","value":"91654"},
+				{"ci_id":"436","ci_attribute_id":"3","attribute_name":"emp_berlin_office","attribute_type":"input","value":"B1"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"moved to vienna"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"1003"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_setCiTypeOfCi##{"query":{"params":{"argv1":"436","argv2":"14","argv3":"1003"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/436##{"ci":{"attributes":[` +
+				`{"mode":"insert","name":"emp_personnel_number","value":"91654","ciAttributeId":0,"uploadId":""},` +
+				`{"mode":"delete","name":"emp_staff_number","value":"","ciAttributeId":2,"uploadId":""}]}}`,
+			ReturnString: emptyResult,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "emp_germany_berlin", 12)
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "emp_austria_vienna", 14)
+
+	mapping := map[string]string{"emp_staff_number": "emp_personnel_number"}
+	wantReport := func(dryRun bool) CiTypeChangeReport {
+		return CiTypeChangeReport{
+			DryRun:   dryRun,
+			CiId:     436,
+			OldType:  "emp_germany_berlin",
+			NewType:  "emp_austria_vienna",
+			Kept:     []string{"emp_firstname"},
+			Migrated: []AttributeMigration{{From: "emp_staff_number", To: "emp_personnel_number", Values: []string{"91654"}}},
+			Lost: CiAttributes{
+				{CiID: 436, CiAttributeID: 3, AttributeName: "emp_berlin_office", AttributeType: "input", Value: "B1"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		newType    string
+		mapping    map[string]string
+		opts       ChangeCiTypeOptions
+		want       CiTypeChangeReport
+		wantString string
+		wantErr    string
+	}{
+		{
+			name:       "dry run",
+			newType:    "emp_austria_vienna",
+			mapping:    mapping,
+			opts:       ChangeCiTypeOptions{DryRun: true},
+			want:       wantReport(true),
+			wantString: "dry run: 436 emp_germany_berlin -> emp_austria_vienna: 1 kept, 1 migrated, 1 lost",
+		},
+		{
+			name:    "data loss",
+			newType: "emp_austria_vienna",
+			mapping: mapping,
+			want:    wantReport(false),
+			wantErr: ErrCiTypeChangeDataLoss.Error(),
+		},
+		{
+			name:    "mapping to disallowed attribute",
+			newType: "emp_austria_vienna",
+			mapping: map[string]string{"emp_staff_number": "emp_berlin_office"},
+			wantErr: "not allowed for emp_austria_vienna",
+		},
+		{
+			name:    "two attributes mapped to the same attribute",
+			newType: "emp_austria_vienna",
+			mapping: map[string]string{"emp_berlin_office": "emp_personnel_number", "emp_staff_number": "emp_personnel_number"},
+			wantErr: "emp_personnel_number is already mapped from emp_berlin_office",
+		},
+		{
+			name:    "mapping to mapped attribute",
+			newType: "emp_austria_vienna",
+			mapping: map[string]string{"emp_staff_number": "emp_personnel_number", "emp_personnel_number": "emp_lastname"},
+			wantErr: "emp_personnel_number is mapped itself",
+		},
+		{
+			name:    "same type",
+			newType: "emp_germany_berlin",
+			wantErr: "already set",
+		},
+		{
+			name:       "change",
+			newType:    "emp_austria_vienna",
+			mapping:    mapping,
+			opts:       ChangeCiTypeOptions{AllowDataLoss: true, UserId: 7, HistoryMessage: "moved to vienna"},
+			want:       wantReport(false),
+			wantString: "436 emp_germany_berlin -> emp_austria_vienna: 1 kept, 1 migrated, 1 lost",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.ChangeCiType(436, tt.newType, tt.mapping, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ChangeCiType() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.want.CiId != 0 && !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ChangeCiType() got = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ChangeCiType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChangeCiType() got = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("String() got = %v, want %v", got.String(), tt.wantString)
+			}
+		})
+	}
+}
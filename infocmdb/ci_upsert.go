@@ -0,0 +1,232 @@
+package infocmdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type UpsertStatus string
+
+const (
+	UPSERT_STATUS_CREATED   UpsertStatus = "created"
+	UPSERT_STATUS_UPDATED   UpsertStatus = "updated"
+	UPSERT_STATUS_UNCHANGED UpsertStatus = "unchanged"
+)
+
+type UpsertResult struct {
+	CiId   int
+	Status UpsertStatus
+	// Names of the attributes that were written, all attributes if the ci was created
+	ChangedAttributes []string
+	// Names of the projects the ci was added to
+	AddedProjects []string
+}
+
+// UpsertCi creates or updates the ci of the given type identified by the values of the key attributes.
+//
+// A new ci gets the key attributes, the attributes and the projects. Of an existing ci only the attributes
+// whose value differs are written and missing projects are added. If more than one ci matches the key,
+// an error containing their ids is returned and nothing is changed.
+// Attributes must not contain a key attribute, otherwise the ci would no longer be found by its key.
+// The userId is used for the history entry of a new ci.
+func (c *Client) UpsertCi(ciTypeName string, keyAttributes map[string]string, attributes map[string]string, projects []string, userId int) (result UpsertResult, err error) {
+	if len(keyAttributes) == 0 {
+		return result, errors.New("missing key attributes")
+	}
+	for _, name := range sortedNames(attributes) {
+		if _, isKey := keyAttributes[name]; isKey {
+			err = utilError.FunctionError(ciTypeName + " - attribute " + name + " is a key attribute")
+			log.Error("Error: ", err)
+			return
+		}
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	ciIds, err := c.getListOfCiIdsByKey(ciTypeName, keyAttributes)
+	if err != nil {
+		return
+	}
+
+	desired := mergeAttributes(keyAttributes, attributes)
+
+	switch len(ciIds) {
+	case 0:
+		result.CiId, err = c.CreateCiWithAttributes(CreateCiRequest{
+			CiType:         ciTypeName,
+			Projects:       projects,
+			Attributes:     desired,
+			HistoryMessage: "upsert " + ciTypeName + " " + formatKey(keyAttributes),
+			UserId:         userId,
+		})
+		if err != nil {
+			return
+		}
+		result.Status = UPSERT_STATUS_CREATED
+		result.ChangedAttributes = sortedNames(desired)
+		result.AddedProjects = projects
+		return
+	case 1:
+		result.CiId = ciIds[0]
+	default:
+		err = utilError.FunctionError(fmt.Sprintf("%s %s - %s: %v", ciTypeName, formatKey(keyAttributes), v2.ErrTooManyResults.Error(), ciIds))
+		log.Error("Error: ", err)
+		return
+	}
+
+	currentAttributes, err := c.GetCiAttributes(result.CiId)
+	if err != nil {
+		return
+	}
+	if result.ChangedAttributes, err = c.updateChangedAttributes(result.CiId, currentAttributes, desired); err != nil {
+		return
+	}
+	if result.AddedProjects, err = c.addMissingProjects(result.CiId, projects); err != nil {
+		return
+	}
+
+	result.Status = UPSERT_STATUS_UNCHANGED
+	if len(result.ChangedAttributes) > 0 || len(result.AddedProjects) > 0 {
+		result.Status = UPSERT_STATUS_UPDATED
+	}
+	return
+}
+
+// getListOfCiIdsByKey returns the ids of the cis of the type having all the given attribute values.
+func (c *Client) getListOfCiIdsByKey(ciTypeName string, keyAttributes map[string]string) (ciIds CiIds, err error) {
+	ciIds, err = c.GetListOfCiIdsOfCiTypeName(ciTypeName)
+	if err != nil {
+		return
+	}
+
+	for _, name := range sortedNames(keyAttributes) {
+		if len(ciIds) == 0 {
+			return
+		}
+
+		var matching CiIds
+		if matching, err = c.GetListOfCiIdsByAttributeValue(name, keyAttributes[name], v2.ATTRIBUTE_VALUE_TYPE_TEXT); err != nil {
+			return nil, err
+		}
+		ciIds = intersectCiIds(ciIds, matching)
+	}
+
+	return
+}
+
+// updateChangedAttributes sets the desired attributes whose current values differ and returns their names.
+func (c *Client) updateChangedAttributes(ciId int, current CiAttributes, desired map[string]string) (changed []string, err error) {
+	changed = changedAttributes(current, desired)
+	if len(changed) == 0 {
+		return
+	}
+
+	updates := make([]v2.UpdateCiAttribute, len(changed))
+	for i, name := range changed {
+		updates[i] = v2.UpdateCiAttribute{Mode: v2.UPDATE_MODE_SET, Name: name, Value: desired[name]}
+	}
+
+	if err = c.UpdateCiAttribute(ciId, updates); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// addMissingProjects adds the ci to the projects it is not part of yet and returns their names.
+func (c *Client) addMissingProjects(ciId int, projects []string) (added []string, err error) {
+	if len(projects) == 0 {
+		return
+	}
+
+	ci, err := c.GetCi(ciId)
+	if err != nil {
+		return
+	}
+
+	for _, project := range projects {
+		projectId, err := c.GetProjectIdByProjectName(project)
+		if err != nil {
+			return added, err
+		}
+		if containsInt(ci.ProjectIDs, projectId) {
+			continue
+		}
+		if err = c.AddCiProjectMapping(ciId, projectId, 0); err != nil {
+			return added, err
+		}
+		added = append(added, project)
+	}
+
+	return
+}
+
+// changedAttributes returns the sorted names of the desired attributes that don't have exactly the desired value.
+func changedAttributes(current CiAttributes, desired map[string]string) (changed []string) {
+	currentValues := map[string][]string{}
+	for _, attribute := range current {
+		currentValues[attribute.AttributeName] = append(currentValues[attribute.AttributeName], attribute.Value)
+	}
+
+	for _, name := range sortedNames(desired) {
+		values := currentValues[name]
+		if len(values) != 1 || values[0] != desired[name] {
+			changed = append(changed, name)
+		}
+	}
+	return
+}
+
+// mergeAttributes returns all attributes of the given maps, later maps take precedence.
+func mergeAttributes(attributeMaps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, attributes := range attributeMaps {
+		for name, value := range attributes {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+func sortedNames(attributes map[string]string) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatKey(keyAttributes map[string]string) string {
+	parts := make([]string, 0, len(keyAttributes))
+	for _, name := range sortedNames(keyAttributes) {
+		parts = append(parts, name+"="+keyAttributes[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+func intersectCiIds(a CiIds, b CiIds) (intersection CiIds) {
+	for _, ciId := range a {
+		if containsInt(b, ciId) {
+			intersection = append(intersection, ciId)
+		}
+	}
+	return
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func newUpsertTestClient(mockings []utilTesting.Mocking) *Client {
+	cmdb := newTestClient(append([]utilTesting.Mocking{{
+		RequestString: `PUT##/apiV2/query/execute/int_getListOfCiIdsOfCiType##{"query":{"params":{"argv1":"12"}}}`,
+		ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ciid":"436"},{"ciid":"437"},{"ciid":"438"},{"ciid":"439"}]}`,
+	}}, mockings...))
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "emp_germany_berlin", 12)
+	cmdb.metadataCache().Set(METADATA_ATTRIBUTE, "emp_staff_number", 3356)
+	cmdb.metadataCache().Set(METADATA_PROJECT, "springfield", 33)
+	cmdb.metadataCache().Set(METADATA_PROJECT, "shelbyville", 34)
+	return cmdb
+}
+
+func TestClient_UpsertCi(t *testing.T) {
+	cmdb := newUpsertTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiIdByCiAttributeValue##{"query":{"params":{"argv1":"3356","argv2":"91651","argv3":"value_text"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"436"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiIdByCiAttributeValue##{"query":{"params":{"argv1":"3356","argv2":"91652","argv3":"value_text"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"437"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiIdByCiAttributeValue##{"query":{"params":{"argv1":"3356","argv2":"91653","argv3":"value_text"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"438"},{"ci_id":"439"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"436","attribute_name":"emp_staff_number","attribute_type":"input","value":"91651"},
+				{"ci_id":"436","attribute_name":"emp_firstname","attribute_type":"input","value":"Cornelia"},
+				{"ci_id":"436","attribute_name":"emp_lastname","attribute_type":"input","value":"Blank"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"437"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"437","attribute_name":"emp_staff_number","attribute_type":"input","value":"91652"},
+				{"ci_id":"437","attribute_name":"emp_firstname","attribute_type":"input","value":"Ralph"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"436"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"436","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCi##{"query":{"params":{"argv1":"437"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"437","ci_type_id":"12","ci_type":"emp_germany_berlin","project":"springfield","project_id":"33"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/436##{"ci":{"attributes":[{"mode":"set","name":"emp_lastname","value":"Wiggum","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"436","argv2":"34","argv3":"0"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":null}`,
+		},
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		attributes map[string]string
+		projects   []string
+		want       UpsertResult
+		wantErr    bool
+	}{
+		{
+			"changed attribute and new project",
+			"91651",
+			map[string]string{"emp_firstname": "Cornelia", "emp_lastname": "Wiggum"},
+			[]string{"springfield", "shelbyville"},
+			UpsertResult{CiId: 436, Status: UPSERT_STATUS_UPDATED, ChangedAttributes: []string{"emp_lastname"}, AddedProjects: []string{"shelbyville"}},
+			false,
+		},
+		{
+			"unchanged",
+			"91652",
+			map[string]string{"emp_firstname": "Ralph"},
+			[]string{"springfield"},
+			UpsertResult{CiId: 437, Status: UPSERT_STATUS_UNCHANGED},
+			false,
+		},
+		{
+			"duplicate key",
+			"91653",
+			map[string]string{"emp_firstname": "Lisa"},
+			nil,
+			UpsertResult{},
+			true,
+		},
+		{
+			"key attribute in attributes",
+			"91651",
+			map[string]string{"emp_staff_number": "91659"},
+			nil,
+			UpsertResult{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.UpsertCi("emp_germany_berlin", map[string]string{"emp_staff_number": tt.key}, tt.attributes, tt.projects, 7)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpsertCi() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpsertCi() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_UpsertCi_create(t *testing.T) {
+	cmdb := newUpsertTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiIdByCiAttributeValue##{"query":{"params":{"argv1":"3356","argv2":"91654","argv3":"value_text"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"1"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"upsert emp_germany_berlin emp_staff_number=91654"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"59529040"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCi##{"query":{"params":{"argv1":"12","argv2":"","argv3":"59529040"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"440","ci_type_id":"12","icon":"","history_id":"59529040"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiProjectMapping##{"query":{"params":{"argv1":"440","argv2":"33","argv3":"59529040"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":null}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/ci/440##{"ci":{"attributes":[{"mode":"set","name":"emp_firstname","value":"Bart","ciAttributeId":0,"uploadId":""},{"mode":"set","name":"emp_staff_number","value":"91654","ciAttributeId":0,"uploadId":""}]}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	got, err := cmdb.UpsertCi("emp_germany_berlin", map[string]string{"emp_staff_number": "91654"}, map[string]string{"emp_firstname": "Bart"}, []string{"springfield"}, 7)
+	if err != nil {
+		t.Fatalf("UpsertCi() error = %v", err)
+	}
+
+	want := UpsertResult{
+		CiId:              440,
+		Status:            UPSERT_STATUS_CREATED,
+		ChangedAttributes: []string{"emp_firstname", "emp_staff_number"},
+		AddedProjects:     []string{"springfield"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UpsertCi() got = %+v, want %+v", got, want)
+	}
+}
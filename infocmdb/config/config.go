@@ -1,13 +1,352 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/transport"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Environment variables overriding values of the config file.
+const (
+	ENV_API_URL           = "INFOCMDB_API_URL"
+	ENV_API_USER          = "INFOCMDB_API_USER"
+	ENV_API_PASSWORD      = "INFOCMDB_API_PASSWORD"
+	ENV_API_PASSWORD_FILE = "INFOCMDB_API_PASSWORD_FILE"
+	ENV_API_TOKEN         = "INFOCMDB_API_TOKEN"
+	ENV_TLS_SKIP_VERIFY   = "INFOCMDB_TLS_INSECURE_SKIP_VERIFY"
+	ENV_PROFILE           = "INFOCMDB_PROFILE"
+	ENV_TOKEN_CACHE_DIR   = "INFOCMDB_TOKEN_CACHE_DIR"
+)
+
+// Connection settings shared by the v1 and v2 api.
+//
+// Example config file with profiles:
+//
+//	apiUser: workflow
+//	apiPasswordFile: /run/secrets/infocmdb_password
+//	profile: dev
+//	profiles:
+//	  dev:
+//	    apiUrl: http://infocmdb.dev.local
+//	  prod:
+//	    apiUrl: https://infocmdb.example.com
+//	    apiPassword: ${INFOCMDB_PROD_PASSWORD}
+//	    tlsCaFile: /etc/pki/internal-ca.pem
+//	    proxyUrl: http://proxy.example.com:3128
+//	    timeout: 30s
+type Config struct {
+	ApiUrl          string `yaml:"apiUrl"`
+	ApiUser         string `yaml:"apiUser"`
+	ApiPassword     string `yaml:"apiPassword"`
+	ApiPasswordFile string `yaml:"apiPasswordFile"`
+	// Static token of the v2 api, the v1 api always requires ApiUser and ApiPassword
+	ApiToken     string `yaml:"apiToken"`
+	CmdbBasePath string `yaml:"CmdbBasePath"`
+	BasePath     string `yaml:"BasePath"`
+	// Directory of the persistent token cache, disabled if empty
+	TokenCacheDir string `yaml:"tokenCacheDir"`
+	// Duration ids of ci types, attributes, etc. are cached
+	MetadataCacheTtl string `yaml:"metadataCacheTtl"`
+	// File the metadata cache is persisted to, disabled if empty
+	MetadataCacheFile string `yaml:"metadataCacheFile"`
+
+	// transport settings, see `transport.Options`
+	TlsCaFile             string `yaml:"tlsCaFile"`
+	TlsCertFile           string `yaml:"tlsCertFile"`
+	TlsKeyFile            string `yaml:"tlsKeyFile"`
+	TlsInsecureSkipVerify bool   `yaml:"tlsInsecureSkipVerify"`
+	ProxyUrl              string `yaml:"proxyUrl"`
+	Timeout               string `yaml:"timeout"`
+}
+
+// Structure of a config file: top level values are shared by all profiles.
+type configFile struct {
+	Config   `yaml:",inline"`
+	Version  string                    `yaml:"version"`
+	Profile  string                    `yaml:"profile"`
+	Profiles map[string]configOverride `yaml:"profiles"`
+}
+
+// Values of a profile or the env variables that override the top level values.
+type configOverride struct {
+	Config
+	// set if tlsInsecureSkipVerify is given, so that it can be reset to false
+	tlsInsecureSkipVerifySet bool
+}
+
+func (override *configOverride) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&override.Config); err != nil {
+		return err
+	}
+
+	var set struct {
+		TlsInsecureSkipVerify *bool `yaml:"tlsInsecureSkipVerify"`
+	}
+	if err := unmarshal(&set); err != nil {
+		return err
+	}
+	override.tlsInsecureSkipVerifySet = set.TlsInsecureSkipVerify != nil
+	return nil
+}
+
+// Loads the connection settings.
+//
+// The config file is resolved like in `LoadYamlConfig`, `${ENV_VARIABLE}` and `${ENV_VARIABLE:-default}`
+// placeholders in string values are replaced with the value of the environment variable after parsing.
+// The profile is selected by the INFOCMDB_PROFILE env variable or the `profile` key,
+// values of the profile override the top level values.
+// Afterwards the INFOCMDB_API_* env variables are applied, which also allows running without a config file.
+// If no password is given, it is read from the `apiPasswordFile`.
+// Relative paths of files (password, certificates, token cache) are resolved relative to the config file.
+func Load(path string) (config Config, err error) {
+	source, err := readConfigSource(path)
+	if err != nil {
+		return
+	}
+
+	return source.load(path)
+}
+
+// Content of a config file, empty if the config is provided via env variables only.
+type configSource struct {
+	resolvedPath string
+	content      []byte
+}
+
+// readConfigSource reads the config file once, so that it can be validated and loaded without reading it again.
+func readConfigSource(path string) (source configSource, err error) {
+	if path == "" {
+		return
+	}
+
+	resolvedPath, err := resolveAbsoluteConfigFilePath(path)
+	switch {
+	case os.IsNotExist(err) && os.Getenv(ENV_API_URL) != "":
+		log.Debugf("Config file %s not found, using environment only", resolvedPath)
+		return source, nil
+	case err != nil:
+		return
+	}
+
+	source.resolvedPath = resolvedPath
+	source.content, err = ioutil.ReadFile(resolvedPath)
+	if err != nil {
+		return
+	}
+
+	log.Tracef("Config file content:\n%s", redact.String(string(source.content)))
+	return
+}
+
+func (source configSource) load(path string) (config Config, err error) {
+	file := configFile{}
+	configDir := ""
+
+	if source.resolvedPath != "" {
+		if err = yaml.Unmarshal(source.content, &file); err != nil {
+			return
+		}
+		interpolateEnvValues(reflect.ValueOf(&file))
+		configDir = filepath.Dir(source.resolvedPath)
+	}
+
+	config = file.Config
+
+	profile := file.Profile
+	if envProfile := os.Getenv(ENV_PROFILE); envProfile != "" {
+		profile = envProfile
+	}
+	if profile != "" {
+		profileConfig, ok := file.Profiles[profile]
+		if !ok {
+			return config, fmt.Errorf("config profile \"%s\" not found in %s", profile, path)
+		}
+		log.Debugf("Using config profile: %s", profile)
+		config.merge(profileConfig)
+	}
+
+	envConfig := configOverride{Config: Config{
+		ApiUrl:          os.Getenv(ENV_API_URL),
+		ApiUser:         os.Getenv(ENV_API_USER),
+		ApiPassword:     os.Getenv(ENV_API_PASSWORD),
+		ApiPasswordFile: os.Getenv(ENV_API_PASSWORD_FILE),
+		ApiToken:        os.Getenv(ENV_API_TOKEN),
+		TokenCacheDir:   os.Getenv(ENV_TOKEN_CACHE_DIR),
+	}}
+	if skipVerify := os.Getenv(ENV_TLS_SKIP_VERIFY); skipVerify != "" {
+		if envConfig.TlsInsecureSkipVerify, err = strconv.ParseBool(skipVerify); err != nil {
+			return config, fmt.Errorf("invalid %s \"%s\": %v", ENV_TLS_SKIP_VERIFY, skipVerify, err)
+		}
+		envConfig.tlsInsecureSkipVerifySet = true
+	}
+	config.merge(envConfig)
+
+	if config.ApiPassword == "" && config.ApiPasswordFile != "" {
+		config.ApiPassword, err = readPasswordFile(resolveRelativePath(config.ApiPasswordFile, configDir))
+		if err != nil {
+			return
+		}
+	}
+
+	config.TlsCaFile = resolveRelativePath(config.TlsCaFile, configDir)
+	config.TlsCertFile = resolveRelativePath(config.TlsCertFile, configDir)
+	config.TlsKeyFile = resolveRelativePath(config.TlsKeyFile, configDir)
+	config.TokenCacheDir = resolveRelativePath(config.TokenCacheDir, configDir)
+	config.MetadataCacheFile = resolveRelativePath(config.MetadataCacheFile, configDir)
+
+	redact.AddSecrets(config.ApiPassword, config.ApiToken)
+	log.Debugf("Config: %s", redact.Struct(config))
+	return
+}
+
+// merge overrides all values with the non-empty values of other, tlsInsecureSkipVerify if it is set.
+func (config *Config) merge(other configOverride) {
+	override := func(value *string, otherValue string) {
+		if otherValue != "" {
+			*value = otherValue
+		}
+	}
+
+	override(&config.ApiUrl, other.ApiUrl)
+	override(&config.ApiUser, other.ApiUser)
+	override(&config.ApiPassword, other.ApiPassword)
+	override(&config.ApiPasswordFile, other.ApiPasswordFile)
+	override(&config.ApiToken, other.ApiToken)
+	override(&config.CmdbBasePath, other.CmdbBasePath)
+	override(&config.BasePath, other.BasePath)
+	override(&config.TokenCacheDir, other.TokenCacheDir)
+	override(&config.MetadataCacheTtl, other.MetadataCacheTtl)
+	override(&config.MetadataCacheFile, other.MetadataCacheFile)
+	override(&config.TlsCaFile, other.TlsCaFile)
+	override(&config.TlsCertFile, other.TlsCertFile)
+	override(&config.TlsKeyFile, other.TlsKeyFile)
+	override(&config.ProxyUrl, other.ProxyUrl)
+	override(&config.Timeout, other.Timeout)
+	if other.tlsInsecureSkipVerifySet {
+		config.TlsInsecureSkipVerify = other.TlsInsecureSkipVerify
+	}
+
+	// an explicitly configured password file replaces a password inherited from the top level
+	if other.ApiPasswordFile != "" && other.ApiPassword == "" {
+		config.ApiPassword = ""
+	}
+}
+
+// TransportOptions returns the transport settings of the config.
+func (config Config) TransportOptions() (options transport.Options, err error) {
+	options = transport.Options{
+		CaFile:             config.TlsCaFile,
+		CertFile:           config.TlsCertFile,
+		KeyFile:            config.TlsKeyFile,
+		InsecureSkipVerify: config.TlsInsecureSkipVerify,
+		ProxyUrl:           config.ProxyUrl,
+	}
+
+	if config.Timeout != "" {
+		options.Timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil {
+			return options, fmt.Errorf("invalid timeout \"%s\": %v", config.Timeout, err)
+		}
+	}
+
+	return
+}
+
+// MetadataCacheTTL returns the configured duration of the metadata cache, 0 if not set.
+func (config Config) MetadataCacheTTL() (ttl time.Duration, err error) {
+	if config.MetadataCacheTtl == "" {
+		return
+	}
+
+	ttl, err = time.ParseDuration(config.MetadataCacheTtl)
+	if err != nil {
+		return 0, fmt.Errorf("invalid metadataCacheTtl \"%s\": %v", config.MetadataCacheTtl, err)
+	}
+	return
+}
+
+// resolveRelativePath resolves paths relative to the directory of the config file.
+func resolveRelativePath(path string, configDir string) string {
+	if path == "" || filepath.IsAbs(path) || configDir == "" {
+		return path
+	}
+	return filepath.Join(configDir, path)
+}
+
+func readPasswordFile(path string) (password string, err error) {
+	passwordBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.New("failed to read password file: " + err.Error())
+	}
+
+	return strings.TrimRight(string(passwordBytes), "\r\n"), nil
+}
+
+var envPlaceholderPattern = regexp.MustCompile(`\$\{(\w+)(?::-([^}]*))?\}`)
+
+// interpolateEnv replaces `${ENV_VARIABLE}` and `${ENV_VARIABLE:-default}` placeholders.
+func interpolateEnv(s string) string {
+	return envPlaceholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		groups := envPlaceholderPattern.FindStringSubmatch(placeholder)
+		if value, ok := os.LookupEnv(groups[1]); ok && value != "" {
+			return value
+		}
+		return groups[2]
+	})
+}
+
+// interpolateEnvValues replaces the placeholders in all strings of the parsed config,
+// so that values of env variables can't change the yaml structure.
+// Unexported struct fields are skipped.
+func interpolateEnvValues(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			interpolateEnvValues(value.Elem())
+		}
+	case reflect.Interface:
+		if value.IsNil() || !value.CanSet() {
+			return
+		}
+		elem := reflect.New(value.Elem().Type()).Elem()
+		elem.Set(value.Elem())
+		interpolateEnvValues(elem)
+		value.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath == "" {
+				interpolateEnvValues(value.Field(i))
+			}
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			elem := reflect.New(value.Type().Elem()).Elem()
+			elem.Set(value.MapIndex(key))
+			interpolateEnvValues(elem)
+			value.SetMapIndex(key, elem)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			interpolateEnvValues(value.Index(i))
+		}
+	case reflect.String:
+		if value.CanSet() {
+			value.SetString(interpolateEnv(value.String()))
+		}
+	}
+}
+
 // Loads a workflow configuration file.
 //
 // If the given path is a absolute path to an existing file,
@@ -38,18 +377,27 @@ func resolveAbsoluteConfigFilePath(path string) (string, error) {
 }
 
 func parseYamlConfig(path string, config interface{}) (err error) {
-	configBytes, err := ioutil.ReadFile(path)
+	err = parseYamlConfigFile(path, config)
 	if err != nil {
 		return
 	}
 
-	log.Tracef("Config file content:\n%s", configBytes)
+	log.Debugf("Config: %s", redact.Struct(config))
+	return
+}
 
-	err = yaml.Unmarshal(configBytes, config)
+func parseYamlConfigFile(path string, config interface{}) (err error) {
+	configBytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		return
 	}
 
-	log.Debugf("Config: %+v", config)
+	log.Tracef("Config file content:\n%s", redact.String(string(configBytes)))
+
+	if err = yaml.Unmarshal(configBytes, config); err != nil {
+		return
+	}
+
+	interpolateEnvValues(reflect.ValueOf(config))
 	return
 }
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func setTestEnv(t *testing.T, env map[string]string) func() {
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatalf("failed to set env %s: %v", key, err)
+		}
+	}
+	return func() {
+		for key := range env {
+			_ = os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "infocmdb-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, dir, "password.txt", "from-file\n")
+	simple := writeTestFile(t, dir, "simple.yml", `version: 1.0
+apiUrl: http://localhost
+apiUser: admin
+apiPassword: admin
+`)
+	profiles := writeTestFile(t, dir, "profiles.yml", `apiUser: workflow
+apiPassword: shared
+profile: dev
+profiles:
+  dev:
+    apiUrl: http://dev.local
+  prod:
+    apiUrl: ${TEST_INFOCMDB_PROD_URL:-https://prod.local}
+    apiPasswordFile: password.txt
+`)
+	skipVerify := writeTestFile(t, dir, "skip-verify.yml", `apiUrl: http://localhost
+apiUser: admin
+apiPassword: ${TEST_INFOCMDB_PASSWORD:-admin}
+tlsInsecureSkipVerify: true
+profiles:
+  strict:
+    tlsInsecureSkipVerify: false
+  other:
+    apiUser: other
+`)
+
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "simple file",
+			path: simple,
+			want: Config{ApiUrl: "http://localhost", ApiUser: "admin", ApiPassword: "admin"},
+		},
+		{
+			name: "env overrides file",
+			path: simple,
+			env:  map[string]string{ENV_API_URL: "http://env.local", ENV_API_PASSWORD: "env"},
+			want: Config{ApiUrl: "http://env.local", ApiUser: "admin", ApiPassword: "env"},
+		},
+		{
+			name: "token cache dir from env",
+			path: simple,
+			env:  map[string]string{ENV_TOKEN_CACHE_DIR: "/var/cache/infocmdb"},
+			want: Config{ApiUrl: "http://localhost", ApiUser: "admin", ApiPassword: "admin", TokenCacheDir: "/var/cache/infocmdb"},
+		},
+		{
+			name: "default profile",
+			path: profiles,
+			want: Config{ApiUrl: "http://dev.local", ApiUser: "workflow", ApiPassword: "shared"},
+		},
+		{
+			name: "profile from env with default interpolation and password file",
+			path: profiles,
+			env:  map[string]string{ENV_PROFILE: "prod"},
+			want: Config{ApiUrl: "https://prod.local", ApiUser: "workflow", ApiPassword: "from-file", ApiPasswordFile: "password.txt"},
+		},
+		{
+			name: "interpolation",
+			path: profiles,
+			env:  map[string]string{ENV_PROFILE: "prod", "TEST_INFOCMDB_PROD_URL": "https://interpolated.local"},
+			want: Config{ApiUrl: "https://interpolated.local", ApiUser: "workflow", ApiPassword: "from-file", ApiPasswordFile: "password.txt"},
+		},
+		{
+			name: "interpolated value with yaml syntax",
+			path: skipVerify,
+			env:  map[string]string{"TEST_INFOCMDB_PASSWORD": "a#b: 'c\"\nproxyUrl: http://injected"},
+			want: Config{ApiUrl: "http://localhost", ApiUser: "admin", ApiPassword: "a#b: 'c\"\nproxyUrl: http://injected", TlsInsecureSkipVerify: true},
+		},
+		{
+			name: "profile resets tls skip verify",
+			path: skipVerify,
+			env:  map[string]string{ENV_PROFILE: "strict"},
+			want: Config{ApiUrl: "http://localhost", ApiUser: "admin", ApiPassword: "admin"},
+		},
+		{
+			name: "profile keeps tls skip verify",
+			path: skipVerify,
+			env:  map[string]string{ENV_PROFILE: "other"},
+			want: Config{ApiUrl: "http://localhost", ApiUser: "other", ApiPassword: "admin", TlsInsecureSkipVerify: true},
+		},
+		{
+			name: "env resets tls skip verify",
+			path: skipVerify,
+			env:  map[string]string{ENV_TLS_SKIP_VERIFY: "false"},
+			want: Config{ApiUrl: "http://localhost", ApiUser: "admin", ApiPassword: "admin"},
+		},
+		{
+			name:    "invalid tls skip verify env",
+			path:    skipVerify,
+			env:     map[string]string{ENV_TLS_SKIP_VERIFY: "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown profile",
+			path:    profiles,
+			env:     map[string]string{ENV_PROFILE: "test"},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			path:    filepath.Join(dir, "missing.yml"),
+			wantErr: true,
+		},
+		{
+			name: "missing file with env config",
+			path: filepath.Join(dir, "missing.yml"),
+			env:  map[string]string{ENV_API_URL: "http://env.local", ENV_API_TOKEN: "token"},
+			want: Config{ApiUrl: "http://env.local", ApiToken: "token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setTestEnv(t, tt.env)()
+
+			got, err := Load(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Load() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// Options of the config validation.
+type ValidationOptions struct {
+	// Check that the host of the api url accepts connections
+	CheckReachable bool
+	// Timeout of the reachability check, defaults to 5 seconds
+	Timeout time.Duration
+	// Log unknown keys (e.g. keys shared with other tools) as warnings instead of reporting them as problems
+	AllowUnknownKeys bool
+}
+
+// Validates the config file and the resulting connection settings (including env variables).
+//
+// Every problem is returned as separate human readable error:
+// unknown keys (typos), missing required values, invalid api urls and optionally unreachable hosts.
+// An empty result means the config is valid.
+func Validate(path string, options ValidationOptions) (problems utilError.Errors) {
+	_, problems = LoadAndValidate(path, options)
+	return
+}
+
+// LoadAndValidate loads the config like `Load` and validates it like `Validate`, the file is read only once.
+//
+// The config is only usable if no problems are returned.
+func LoadAndValidate(path string, options ValidationOptions) (config Config, problems utilError.Errors) {
+	source, err := readConfigSource(path)
+	if err != nil {
+		return config, problems.Add(fmt.Errorf("failed to load config: %v", err))
+	}
+	if source.resolvedPath != "" {
+		unknownKeys, keyProblems := validateKeys(source)
+		problems = problems.Add(keyProblems...)
+		if options.AllowUnknownKeys {
+			for _, unknownKey := range unknownKeys {
+				log.Warnf("Config: %v", unknownKey)
+			}
+		} else {
+			problems = problems.Add(unknownKeys...)
+		}
+	}
+
+	config, err = source.load(path)
+	if err != nil {
+		return config, problems.Add(fmt.Errorf("failed to load config: %v", err))
+	}
+
+	return config, problems.Add(config.Validate(options)...)
+}
+
+// Validate checks that all required values are set and valid.
+func (config Config) Validate(options ValidationOptions) (problems utilError.Errors) {
+	if config.ApiUrl == "" {
+		problems = problems.Add(fmt.Errorf("missing api url: set \"apiUrl\" or env variable %s", ENV_API_URL))
+	} else if apiUrl, err := url.Parse(config.ApiUrl); err != nil {
+		problems = problems.Add(fmt.Errorf("invalid api url \"%s\": %v", config.ApiUrl, err))
+	} else if apiUrl.Scheme != "http" && apiUrl.Scheme != "https" {
+		problems = problems.Add(fmt.Errorf("invalid api url \"%s\": scheme must be http or https", config.ApiUrl))
+	} else if apiUrl.Host == "" {
+		problems = problems.Add(fmt.Errorf("invalid api url \"%s\": missing host", config.ApiUrl))
+	} else if options.CheckReachable {
+		problems = problems.Add(checkReachable(apiUrl, config.ProxyUrl, options.Timeout))
+	}
+
+	if _, err := config.TransportOptions(); err != nil {
+		problems = problems.Add(err)
+	}
+	if _, err := config.MetadataCacheTTL(); err != nil {
+		problems = problems.Add(err)
+	}
+	if config.ProxyUrl != "" {
+		if proxyUrl, err := url.Parse(config.ProxyUrl); err != nil || proxyUrl.Host == "" {
+			problems = problems.Add(fmt.Errorf("invalid proxy url \"%s\"", config.ProxyUrl))
+		}
+	}
+	if (config.TlsCertFile == "") != (config.TlsKeyFile == "") {
+		problems = problems.Add(fmt.Errorf("client certificate requires both \"tlsCertFile\" and \"tlsKeyFile\""))
+	}
+	for _, file := range [][2]string{
+		{"tlsCaFile", config.TlsCaFile},
+		{"tlsCertFile", config.TlsCertFile},
+		{"tlsKeyFile", config.TlsKeyFile},
+	} {
+		if file[1] == "" {
+			continue
+		}
+		if _, err := os.Stat(file[1]); err != nil {
+			problems = problems.Add(fmt.Errorf("invalid \"%s\": %v", file[0], err))
+		}
+	}
+
+	if config.ApiToken == "" {
+		if config.ApiUser == "" {
+			problems = problems.Add(fmt.Errorf("missing credentials: set \"apiUser\" and \"apiPassword\" or \"apiToken\""))
+		} else if config.ApiPassword == "" {
+			problems = problems.Add(fmt.Errorf("missing password for user \"%s\": set \"apiPassword\" or \"apiPasswordFile\"", config.ApiUser))
+		}
+	}
+
+	return
+}
+
+// checkReachable dials the api host, or the proxy if one is configured.
+func checkReachable(apiUrl *url.URL, proxy string, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	if proxyUrl, err := url.Parse(proxy); proxy != "" && err == nil && proxyUrl.Host != "" {
+		apiUrl = proxyUrl
+	}
+
+	port := apiUrl.Port()
+	if port == "" {
+		port = "80"
+		if apiUrl.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	address := net.JoinHostPort(apiUrl.Hostname(), port)
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return fmt.Errorf("host %s is not reachable: %v", address, err)
+	}
+	return conn.Close()
+}
+
+// validateKeys reports unknown keys of the config file and its profiles, other problems of the structure separately.
+func validateKeys(source configSource) (unknownKeys utilError.Errors, problems utilError.Errors) {
+	resolvedPath := source.resolvedPath
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(source.content, &raw); err != nil {
+		return nil, problems.Add(fmt.Errorf("invalid yaml in %s: %v", resolvedPath, err))
+	}
+
+	configKeys := yamlKeys(reflect.TypeOf(Config{}))
+	fileKeys := append(yamlKeys(reflect.TypeOf(configFile{})), configKeys...)
+
+	for _, key := range sortedKeys(raw) {
+		if !containsKey(fileKeys, key) {
+			unknownKeys = unknownKeys.Add(unknownKeyError(resolvedPath, key, fileKeys))
+		}
+	}
+
+	profiles, _ := raw["profiles"].(map[interface{}]interface{})
+	for profileName, profile := range profiles {
+		profileValues, ok := profile.(map[interface{}]interface{})
+		if !ok {
+			problems = problems.Add(fmt.Errorf("profile \"%v\" in %s must be a map", profileName, resolvedPath))
+			continue
+		}
+
+		profileRaw := map[string]interface{}{}
+		for key, value := range profileValues {
+			profileRaw[fmt.Sprint(key)] = value
+		}
+		for _, key := range sortedKeys(profileRaw) {
+			if !containsKey(configKeys, key) {
+				unknownKeys = unknownKeys.Add(unknownKeyError(fmt.Sprintf("%s (profile \"%v\")", resolvedPath, profileName), key, configKeys))
+			}
+		}
+	}
+
+	return
+}
+
+func unknownKeyError(location string, key string, knownKeys []string) error {
+	suggestion := ""
+	bestDistance := 3
+	for _, knownKey := range knownKeys {
+		distance := levenshtein(strings.ToLower(key), strings.ToLower(knownKey))
+		if distance < bestDistance {
+			bestDistance = distance
+			suggestion = knownKey
+		}
+	}
+
+	if suggestion != "" {
+		return fmt.Errorf("unknown key \"%s\" in %s, did you mean \"%s\"?", key, location, suggestion)
+	}
+	return fmt.Errorf("unknown key \"%s\" in %s", key, location)
+}
+
+// yamlKeys returns the yaml keys of all fields of the given struct type, inlined structs are skipped.
+func yamlKeys(structType reflect.Type) (keys []string) {
+	for i := 0; i < structType.NumField(); i++ {
+		tag := strings.Split(structType.Field(i).Tag.Get("yaml"), ",")
+		if tag[0] != "" && tag[0] != "-" {
+			keys = append(keys, tag[0])
+		}
+	}
+	return
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) (keys []string) {
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
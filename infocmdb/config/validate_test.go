@@ -0,0 +1,117 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "infocmdb-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		content      string
+		options      ValidationOptions
+		wantProblems []string
+	}{
+		{
+			name: "valid",
+			content: `apiUrl: ` + server.URL + `
+apiUser: admin
+apiPassword: admin
+`,
+			options: ValidationOptions{CheckReachable: true},
+		},
+		{
+			name: "valid token only",
+			content: `apiUrl: http://localhost
+apiToken: token
+`,
+		},
+		{
+			name: "typo and missing values",
+			content: `apiUrll: http://localhost
+apiUser: admin
+`,
+			wantProblems: []string{
+				`unknown key "apiUrll" in ` + filepath.Join(dir, "typo and missing values.yml") + `, did you mean "apiUrl"?`,
+				`missing api url: set "apiUrl" or env variable INFOCMDB_API_URL`,
+				`missing password for user "admin": set "apiPassword" or "apiPasswordFile"`,
+			},
+		},
+		{
+			name: "allowed unknown key",
+			content: `apiUrl: http://localhost
+apiToken: token
+sharedKey: value
+`,
+			options: ValidationOptions{AllowUnknownKeys: true},
+		},
+		{
+			name: "allowed unknown key and missing values",
+			content: `apiUrll: http://localhost
+apiToken: token
+`,
+			options: ValidationOptions{AllowUnknownKeys: true},
+			wantProblems: []string{
+				`missing api url: set "apiUrl" or env variable INFOCMDB_API_URL`,
+			},
+		},
+		{
+			name: "unknown profile key",
+			content: `apiUser: admin
+apiPassword: admin
+profile: dev
+profiles:
+  dev:
+    apiUrl: http://localhost
+    colour: blue
+`,
+			wantProblems: []string{
+				`unknown key "colour" in ` + filepath.Join(dir, "unknown profile key.yml") + ` (profile "dev")`,
+			},
+		},
+		{
+			name: "invalid url",
+			content: `apiUrl: localhost:8080
+apiToken: token
+`,
+			wantProblems: []string{
+				`invalid api url "localhost:8080": scheme must be http or https`,
+			},
+		},
+		{
+			name: "missing credentials",
+			content: `apiUrl: http://localhost
+`,
+			wantProblems: []string{
+				`missing credentials: set "apiUser" and "apiPassword" or "apiToken"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, dir, tt.name+".yml", tt.content)
+
+			var gotProblems []string
+			for _, problem := range Validate(path, tt.options) {
+				gotProblems = append(gotProblems, problem.Error())
+			}
+			if !reflect.DeepEqual(gotProblems, tt.wantProblems) {
+				t.Errorf("Validate() got = %q, want %q", gotProblems, tt.wantProblems)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package infocmdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type createHistory struct {
+	UserId  int    `argv:"1"`
+	Message string `argv:"2"`
+}
+
+func (createHistory) QueryName() string { return "int_createHistory" }
+
+// CreateHistory creates a history entry to group the following changes under the given message.
+//
+// Pass the id to `CreateCi`, `AddCiProjectMapping` or `CreateCiWithAttributes` (which creates it on its own if
+// `HistoryMessage` is set), the changes are then shown together in the history of the ci.
+func (c *Client) CreateHistory(userId int, message string) (historyId int, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	row := responseId{}
+	if err = c.queryOne(createHistory{UserId: userId, Message: message}, &row, message); err != nil {
+		return
+	}
+
+	return row.Id.Int(), nil
+}
+
+// CiHistoryEntry is a change of a ci, all attribute changes of one history entry are grouped.
+type CiHistoryEntry struct {
+	HistoryId int
+	UserId    int
+	UserName  string
+	Message   string
+	Timestamp time.Time
+	Changes   []CiAttributeChange
+}
+
+// CiAttributeChange is a changed attribute value, the old value is empty for added
+// and the new value is empty for removed values.
+type CiAttributeChange struct {
+	AttributeId       int
+	Attribute         string
+	AttributeTypeName string
+	OldValue          string
+	NewValue          string
+}
+
+type ciHistoryRow struct {
+	HistoryId         v2.FlexInt    `json:"history_id"`
+	Timestamp         v2.FlexTime   `json:"datestamp"`
+	UserId            v2.FlexInt    `json:"user_id"`
+	UserName          v2.NullString `json:"username"`
+	Message           v2.NullString `json:"note"`
+	AttributeId       v2.FlexInt    `json:"attribute_id"`
+	Attribute         v2.NullString `json:"attribute_name"`
+	AttributeTypeName v2.NullString `json:"attribute_type"`
+	OldValue          v2.NullString `json:"old_value"`
+	NewValue          v2.NullString `json:"new_value"`
+}
+
+type getCiHistory struct {
+	Data []ciHistoryRow `json:"data"`
+}
+
+// GetCiHistory returns the history of the ci, newest entries first.
+//
+// The webservice int_getCiHistory returns one row per changed attribute value, rows without attribute
+// (e.g. the creation of the ci or project changes) result in entries without changes.
+// Old and new values of password and sensitive attributes are masked in the logged response.
+// They are not registered for masking elsewhere, as the history may contain any number of old passwords.
+func (c *Client) GetCiHistory(ciId int) (entries []CiHistoryEntry, err error) {
+	entries = []CiHistoryEntry{}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	params := map[string]string{
+		"argv1": strconv.Itoa(ciId),
+	}
+
+	response := getCiHistory{}
+	err = c.v2.Query("int_getCiHistory", &response, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	index := map[int]int{}
+	for _, row := range response.Data {
+		historyId := row.HistoryId.Int()
+		i, found := index[historyId]
+		if !found {
+			i = len(entries)
+			index[historyId] = i
+			entries = append(entries, CiHistoryEntry{
+				HistoryId: historyId,
+				UserId:    row.UserId.Int(),
+				UserName:  row.UserName.String,
+				Message:   row.Message.String,
+				Timestamp: row.Timestamp.Time,
+				Changes:   []CiAttributeChange{},
+			})
+		}
+
+		if row.Attribute.String == "" {
+			continue
+		}
+		entries[i].Changes = append(entries[i].Changes, CiAttributeChange{
+			AttributeId:       row.AttributeId.Int(),
+			Attribute:         row.Attribute.String,
+			AttributeTypeName: row.AttributeTypeName.String,
+			OldValue:          row.OldValue.String,
+			NewValue:          row.NewValue.String,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.After(entries[j].Timestamp)
+		}
+		return entries[i].HistoryId > entries[j].HistoryId
+	})
+
+	return
+}
+
+// Change returns the change of the attribute in this entry.
+func (e CiHistoryEntry) Change(attributeName string) (change CiAttributeChange, found bool) {
+	for _, change := range e.Changes {
+		if change.Attribute == attributeName {
+			return change, true
+		}
+	}
+	return
+}
@@ -0,0 +1,95 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_CreateHistory(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createHistory##{"query":{"params":{"argv1":"7","argv2":"nightly import"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"1001"}]}`,
+		},
+	})
+
+	got, err := cmdb.CreateHistory(7, "nightly import")
+	if err != nil {
+		t.Fatalf("CreateHistory() error = %v", err)
+	}
+	if got != 1001 {
+		t.Errorf("CreateHistory() got = %v, want %v", got, 1001)
+	}
+}
+
+func TestClient_GetCiHistory(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiHistory##{"query":{"params":{"argv1":"42"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"history_id":"900","datestamp":"2019-11-20 08:00:00","user_id":"7","username":"workflow","note":"ci created",
+				 "attribute_id":null,"attribute_name":null,"attribute_type":null,"old_value":null,"new_value":null},
+				{"history_id":"1001","datestamp":"2019-11-27 15:53:32","user_id":"7","username":"workflow","note":"nightly import",
+				 "attribute_id":"10","attribute_name":"hostname","attribute_type":"input","old_value":"srv-01","new_value":"srv-02"},
+				{"history_id":"1001","datestamp":"2019-11-27 15:53:32","user_id":"7","username":"workflow","note":"nightly import",
+				 "attribute_id":"11","attribute_name":"ip","attribute_type":"input","old_value":null,"new_value":"10.0.0.2"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiHistory##{"query":{"params":{"argv1":"99"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	tests := []struct {
+		name string
+		ciId int
+		want []CiHistoryEntry
+	}{
+		{
+			name: "history",
+			ciId: 42,
+			want: []CiHistoryEntry{
+				{
+					HistoryId: 1001, UserId: 7, UserName: "workflow", Message: "nightly import",
+					Timestamp: time.Date(2019, 11, 27, 15, 53, 32, 0, v2.FlexTimeLocation),
+					Changes: []CiAttributeChange{
+						{AttributeId: 10, Attribute: "hostname", AttributeTypeName: "input", OldValue: "srv-01", NewValue: "srv-02"},
+						{AttributeId: 11, Attribute: "ip", AttributeTypeName: "input", NewValue: "10.0.0.2"},
+					},
+				},
+				{
+					HistoryId: 900, UserId: 7, UserName: "workflow", Message: "ci created",
+					Timestamp: time.Date(2019, 11, 20, 8, 0, 0, 0, v2.FlexTimeLocation),
+					Changes:   []CiAttributeChange{},
+				},
+			},
+		},
+		{name: "no history", ciId: 99, want: []CiHistoryEntry{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.GetCiHistory(tt.ciId)
+			if err != nil {
+				t.Fatalf("GetCiHistory() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCiHistory() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCiHistoryEntry_Change(t *testing.T) {
+	entry := CiHistoryEntry{Changes: []CiAttributeChange{{Attribute: "hostname", OldValue: "srv-01", NewValue: "srv-02"}}}
+
+	if change, found := entry.Change("hostname"); !found || change.NewValue != "srv-02" {
+		t.Errorf("Change(hostname) got = %+v, %v", change, found)
+	}
+	if _, found := entry.Change("ip"); found {
+		t.Errorf("Change(ip) found a change")
+	}
+}
@@ -14,6 +14,10 @@ package infocmdb
 // This api properly handles all permission checks and access to native functions.
 
 import (
+	"net/http"
+	"sync"
+
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/config"
 	v1 "github.com/infonova/infocmdb-sdk-go/infocmdb/v1/infocmdb"
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
 )
@@ -29,9 +33,15 @@ type Config struct {
 }
 
 // Client combines connectivity methods for version 1 and 2 of the cmdb
+//
+// After the config has been loaded, a client is safe for concurrent use by multiple goroutines.
+// Concurrent requests share a single login per api version.
 type Client struct {
 	v1 *v1.Cmdb
 	v2 *v2.Cmdb
+
+	metadataMutex sync.Mutex
+	metadata      MetadataCache
 }
 
 // NewClient returns a new cmdb client
@@ -43,17 +53,60 @@ func NewClient() (c *Client) {
 	return
 }
 
+// SetTransport injects a custom round tripper (e.g. for tracing or special proxies) used by all v1 and v2 requests.
+// TLS and proxy settings of the config are ignored, the configured timeout still applies.
+func (c *Client) SetTransport(roundTripper http.RoundTripper) {
+	c.v1.SetTransport(roundTripper)
+	c.v2.SetTransport(roundTripper)
+}
+
 // LoadConfig from file in yaml format
+//
+// The config is validated first, all problems are returned as `utilError.Errors`.
+// Unknown keys are only logged as warnings, so files shared with other tools keep loading.
 func (c *Client) LoadConfig(path string) (err error) {
-	err = c.v1.LoadConfigFile(path)
+	loadedConfig, problems := config.LoadAndValidate(path, config.ValidationOptions{AllowUnknownKeys: true})
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return c.applyConfig(loadedConfig)
+}
+
+// applyConfig passes the loaded config to the v1 and v2 api and sets up the metadata cache
+func (c *Client) applyConfig(loadedConfig config.Config) (err error) {
+	err = c.v1.ApplyConfig(loadedConfig)
 	if err != nil {
 		return
 	}
 
-	err = c.v2.LoadConfigFile(path)
+	err = c.v2.ApplyConfig(loadedConfig)
 	if err != nil {
 		return
 	}
 
+	return c.loadMetadataCacheConfig(loadedConfig)
+}
+
+// loadMetadataCacheConfig sets up the metadata cache with the configured ttl and file
+func (c *Client) loadMetadataCacheConfig(loadedConfig config.Config) (err error) {
+	ttl, err := loadedConfig.MetadataCacheTTL()
+	if err != nil {
+		return
+	}
+	if ttl == 0 {
+		ttl = DefaultMetadataCacheTTL
+	}
+
+	if loadedConfig.MetadataCacheFile == "" {
+		c.SetMetadataCache(NewMetadataCache(ttl))
+		return
+	}
+
+	cache, err := NewFileMetadataCache(loadedConfig.MetadataCacheFile, ttl)
+	if err != nil {
+		return
+	}
+	c.SetMetadataCache(cache)
 	return
 }
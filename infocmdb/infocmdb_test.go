@@ -0,0 +1,125 @@
+package infocmdb
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	v1 "github.com/infonova/infocmdb-sdk-go/infocmdb/v1/infocmdb"
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+// newTestClient returns a client logged in as admin on the mock server of util/testing.
+// The mockings are added to the default mockings, every request of a test must be mocked.
+func newTestClient(mockings []utilTesting.Mocking) *Client {
+	mock := utilTesting.New()
+	for _, m := range mockings {
+		mock.AddMocking(m)
+	}
+
+	cmdbV2 := v2.New()
+	cmdbV2.LoadConfig(v2.Config{Url: mock.GetUrl(), Username: "admin", Password: "admin"})
+	return &Client{v1: v1.New(), v2: cmdbV2}
+}
+
+type countingRoundTripper struct {
+	requests int64
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&rt.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_SetTransport(t *testing.T) {
+	infocmdbUrl := utilTesting.New().GetUrl()
+
+	cmdbV2 := v2.New()
+	cmdbV2.LoadConfig(v2.Config{
+		Url:      infocmdbUrl,
+		Username: "admin",
+		Password: "admin",
+	})
+	cmdb := &Client{
+		v1: v1.New(),
+		v2: cmdbV2,
+	}
+
+	roundTripper := &countingRoundTripper{}
+	cmdb.SetTransport(roundTripper)
+
+	if _, err := cmdb.GetListOfCiIdsOfCiType(1); err != nil {
+		t.Fatalf("GetListOfCiIdsOfCiType() error = %v", err)
+	}
+
+	// login and query
+	if requests := atomic.LoadInt64(&roundTripper.requests); requests != 2 {
+		t.Errorf("SetTransport() requests = %v, want 2", requests)
+	}
+}
+
+type loginCountingRoundTripper struct {
+	v1Logins int64
+	v2Logins int64
+}
+
+func (rt *loginCountingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.Path, "/api/login/") {
+		atomic.AddInt64(&rt.v1Logins, 1)
+	}
+	if req.URL.Path == "/apiV2/auth/token" {
+		atomic.AddInt64(&rt.v2Logins, 1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_concurrent(t *testing.T) {
+	mock := utilTesting.New()
+
+	cmdbV1 := v1.New()
+	mock.SetValidConfig(&cmdbV1.Config)
+	cmdbV2 := v2.New()
+	cmdbV2.LoadConfig(v2.Config{
+		Url:      mock.GetUrl(),
+		Username: "admin",
+		Password: "admin",
+	})
+	cmdb := &Client{
+		v1: cmdbV1,
+		v2: cmdbV2,
+	}
+
+	roundTripper := &loginCountingRoundTripper{}
+	cmdb.SetTransport(roundTripper)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := cmdb.GetListOfCiIdsOfCiType(1); err != nil {
+				t.Errorf("GetListOfCiIdsOfCiType() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			params := url.Values{}
+			params.Add("argv1", "1")
+			if _, err := cmdb.v1.Webservice("int_getListOfCiIdsOfCiType", params); err != nil {
+				t.Errorf("Webservice() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins := atomic.LoadInt64(&roundTripper.v1Logins); logins != 1 {
+		t.Errorf("concurrent v1 requests logged in %d times, want 1", logins)
+	}
+	if logins := atomic.LoadInt64(&roundTripper.v2Logins); logins != 1 {
+		t.Errorf("concurrent v2 requests logged in %d times, want 1", logins)
+	}
+}
@@ -2,27 +2,121 @@ package infocmdb
 
 import (
 	"bytes"
+	"io"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
 	easy "github.com/t-tomalak/logrus-easy-formatter"
 )
 
-type logOutputSplitter struct{}
+// Output stream a log message is written to.
+type LogStream int
 
+const (
+	LOG_STREAM_STDOUT LogStream = iota + 1
+	LOG_STREAM_STDERR
+)
+
+type logOutputSplitter struct {
+	mutex   sync.RWMutex
+	streams map[log.Level]LogStream
+	// levels whose stream was set with `SetLogStream`
+	configured map[log.Level]bool
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+func newLogOutputSplitter() *logOutputSplitter {
+	return &logOutputSplitter{
+		streams: map[log.Level]LogStream{
+			log.TraceLevel: LOG_STREAM_STDOUT,
+			log.DebugLevel: LOG_STREAM_STDOUT,
+			log.InfoLevel:  LOG_STREAM_STDOUT,
+			log.WarnLevel:  LOG_STREAM_STDERR,
+			log.ErrorLevel: LOG_STREAM_STDERR,
+			log.FatalLevel: LOG_STREAM_STDERR,
+			log.PanicLevel: LOG_STREAM_STDERR,
+		},
+		configured: map[log.Level]bool{},
+		stdout:     os.Stdout,
+		stderr:     os.Stderr,
+	}
+}
+
+func (splitter *logOutputSplitter) setStream(level log.Level, stream LogStream) {
+	splitter.mutex.Lock()
+	defer splitter.mutex.Unlock()
+	splitter.streams[level] = stream
+	splitter.configured[level] = true
+}
+
+// setDefaultStream changes the stream of a level unless it was set with `SetLogStream`.
+func (splitter *logOutputSplitter) setDefaultStream(level log.Level, stream LogStream) {
+	splitter.mutex.Lock()
+	defer splitter.mutex.Unlock()
+	if !splitter.configured[level] {
+		splitter.streams[level] = stream
+	}
+}
+
+// Write dispatches the message depending on the level prefix written by the formatter (e.g. "[WARNING]").
+// Messages without a known level are written to stderr.
 func (splitter *logOutputSplitter) Write(msg []byte) (n int, err error) {
-	if bytes.HasPrefix(msg, []byte("[TRACE]")) ||
-		bytes.HasPrefix(msg, []byte("[DEBUG]")) ||
-		bytes.HasPrefix(msg, []byte("[INFO]")) {
-		return os.Stdout.Write(msg)
+	splitter.mutex.RLock()
+	defer splitter.mutex.RUnlock()
+
+	if bytes.HasPrefix(msg, []byte("[")) {
+		end := bytes.IndexByte(msg, ']')
+		if end > 0 {
+			level, parseErr := log.ParseLevel(strings.ToLower(string(msg[1:end])))
+			if parseErr == nil && splitter.streams[level] == LOG_STREAM_STDOUT {
+				return splitter.stdout.Write(msg)
+			}
+		}
 	}
-	return os.Stderr.Write(msg)
+	return splitter.stderr.Write(msg)
 }
 
+var logSplitter = newLogOutputSplitter()
+
+// SetLogStream changes the output stream of a log level.
+//
+// By default warnings are written to stderr which lets infoCMDB mark the workflow as failed.
+// `workflow.Run` and `workflow.RunWithResult` write warnings to stdout and report them in the result instead.
+// To fail these workflows on warnings again, write them to stderr:
+//
+//	infocmdb.SetLogStream(log.WarnLevel, infocmdb.LOG_STREAM_STDERR)
+func SetLogStream(level log.Level, stream LogStream) {
+	logSplitter.setStream(level, stream)
+}
+
+// warningCounter counts all logged warnings, used to determine the result of a workflow.
+type warningCounter struct {
+	count int64
+}
+
+func (counter *warningCounter) Levels() []log.Level {
+	return []log.Level{log.WarnLevel}
+}
+
+func (counter *warningCounter) Fire(*log.Entry) error {
+	atomic.AddInt64(&counter.count, 1)
+	return nil
+}
+
+func (counter *warningCounter) Count() int {
+	return int(atomic.LoadInt64(&counter.count))
+}
+
+var loggedWarnings = &warningCounter{}
+
 func init() {
 	// Log to stdout and stderr depending on log level:
 	// Any message on stderr is interpreted as workflow failure
-	log.SetOutput(&logOutputSplitter{})
+	log.SetOutput(logSplitter)
 	// Time is omitted in the log message, because it is already shown in the workflow log in a separate column
 	log.SetFormatter(&easy.Formatter{
 		LogFormat: "[%lvl%] %msg%\n",
@@ -31,4 +125,5 @@ func init() {
 	if os.Getenv("WORKFLOW_DEBUGGING") == "true" {
 		log.SetLevel(log.DebugLevel)
 	}
+	log.AddHook(loggedWarnings)
 }
@@ -0,0 +1,81 @@
+package infocmdb
+
+import (
+	"bytes"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func Test_logOutputSplitter_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		streams    map[log.Level]LogStream
+		msg        string
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "info to stdout",
+			msg:        "[INFO] message\n",
+			wantStdout: "[INFO] message\n",
+		},
+		{
+			name:       "warning to stderr by default",
+			msg:        "[WARNING] message\n",
+			wantStderr: "[WARNING] message\n",
+		},
+		{
+			name:       "warning to stdout if configured",
+			streams:    map[log.Level]LogStream{log.WarnLevel: LOG_STREAM_STDOUT},
+			msg:        "[WARNING] message\n",
+			wantStdout: "[WARNING] message\n",
+		},
+		{
+			name:       "debug to stderr if configured",
+			streams:    map[log.Level]LogStream{log.DebugLevel: LOG_STREAM_STDERR},
+			msg:        "[DEBUG] message\n",
+			wantStderr: "[DEBUG] message\n",
+		},
+		{
+			name:       "unknown level to stderr",
+			msg:        "panic: something\n",
+			wantStderr: "panic: something\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			splitter := newLogOutputSplitter()
+			splitter.stdout = &stdout
+			splitter.stderr = &stderr
+			for level, stream := range tt.streams {
+				splitter.setStream(level, stream)
+			}
+
+			if _, err := splitter.Write([]byte(tt.msg)); err != nil {
+				t.Errorf("Write() error = %v", err)
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("Write() stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if stderr.String() != tt.wantStderr {
+				t.Errorf("Write() stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func Test_logOutputSplitter_setDefaultStream(t *testing.T) {
+	splitter := newLogOutputSplitter()
+	splitter.setDefaultStream(log.WarnLevel, LOG_STREAM_STDOUT)
+	if splitter.streams[log.WarnLevel] != LOG_STREAM_STDOUT {
+		t.Errorf("setDefaultStream() stream = %v, want %v", splitter.streams[log.WarnLevel], LOG_STREAM_STDOUT)
+	}
+
+	splitter.setStream(log.WarnLevel, LOG_STREAM_STDERR)
+	splitter.setDefaultStream(log.WarnLevel, LOG_STREAM_STDOUT)
+	if splitter.streams[log.WarnLevel] != LOG_STREAM_STDERR {
+		t.Errorf("setDefaultStream() overrides stream set with SetLogStream, stream = %v", splitter.streams[log.WarnLevel])
+	}
+}
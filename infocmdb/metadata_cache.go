@@ -0,0 +1,294 @@
+package infocmdb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	utilCache "github.com/patrickmn/go-cache"
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// Kind of cached metadata, the names of each kind are unique.
+type MetadataKind string
+
+const (
+	METADATA_CI_TYPE         MetadataKind = "ci_type"
+	METADATA_ATTRIBUTE       MetadataKind = "attribute"
+	METADATA_ATTRIBUTE_GROUP MetadataKind = "attribute_group"
+	METADATA_RELATION_TYPE   MetadataKind = "relation_type"
+	METADATA_PROJECT         MetadataKind = "project"
+)
+
+// Default duration metadata ids are cached.
+const DefaultMetadataCacheTTL = 5 * time.Minute
+
+// Webservices used by `Preload`, each returns the `id` and `name` of all entries.
+const (
+	QUERY_GET_ALL_CI_TYPES         = "int_getAllCiTypes"
+	QUERY_GET_ALL_ATTRIBUTES       = "int_getAllAttributes"
+	QUERY_GET_ALL_ATTRIBUTE_GROUPS = "int_getAllAttributeGroups"
+	QUERY_GET_ALL_RELATION_TYPES   = "int_getAllCiRelationTypes"
+	QUERY_GET_ALL_PROJECTS         = "int_getAllProjects"
+)
+
+// MetadataCache caches the ids of ci types, attributes, attribute groups, relation types and projects by name.
+//
+// Implementations must be safe for concurrent use.
+type MetadataCache interface {
+	Get(kind MetadataKind, name string) (id int, found bool)
+	Set(kind MetadataKind, name string, id int)
+	// SetAll replaces all entries of the given kind
+	SetAll(kind MetadataKind, ids map[string]int)
+	// Invalidate removes all entries of the given kinds, all entries if no kind is given
+	Invalidate(kinds ...MetadataKind)
+}
+
+// NewMetadataCache returns an in-memory cache, entries expire after the given ttl.
+func NewMetadataCache(ttl time.Duration) MetadataCache {
+	return &memoryMetadataCache{
+		cache: utilCache.New(ttl, 2*ttl),
+	}
+}
+
+type memoryMetadataCache struct {
+	cache *utilCache.Cache
+}
+
+func metadataCacheKey(kind MetadataKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+func (m *memoryMetadataCache) Get(kind MetadataKind, name string) (id int, found bool) {
+	cached, found := m.cache.Get(metadataCacheKey(kind, name))
+	if !found {
+		return 0, false
+	}
+	return cached.(int), true
+}
+
+func (m *memoryMetadataCache) Set(kind MetadataKind, name string, id int) {
+	m.cache.Set(metadataCacheKey(kind, name), id, utilCache.DefaultExpiration)
+}
+
+func (m *memoryMetadataCache) SetAll(kind MetadataKind, ids map[string]int) {
+	m.invalidate(kind)
+	for name, id := range ids {
+		m.Set(kind, name, id)
+	}
+}
+
+func (m *memoryMetadataCache) Invalidate(kinds ...MetadataKind) {
+	if len(kinds) == 0 {
+		m.cache.Flush()
+		return
+	}
+	m.invalidate(kinds...)
+}
+
+func (m *memoryMetadataCache) invalidate(kinds ...MetadataKind) {
+	for key := range m.cache.Items() {
+		for _, kind := range kinds {
+			if strings.HasPrefix(key, string(kind)+"/") {
+				m.cache.Delete(key)
+			}
+		}
+	}
+}
+
+// NewFileMetadataCache returns an in-memory cache that is persisted to the given file (permissions 0600),
+// so that short-lived workflows don't have to look up the same ids again.
+// Entries are loaded from the file if it exists, expired entries are skipped.
+func NewFileMetadataCache(path string, ttl time.Duration) (MetadataCache, error) {
+	m := &fileMetadataCache{
+		memoryMetadataCache: memoryMetadataCache{cache: utilCache.New(ttl, 2*ttl)},
+		path:                path,
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]persistedMetadata
+	if err = json.Unmarshal(content, &entries); err != nil {
+		log.Warnf("Ignoring invalid metadata cache file %s: %v", path, err)
+		return m, nil
+	}
+
+	now := time.Now()
+	for key, entry := range entries {
+		if expiresIn := time.Unix(0, entry.Expiration).Sub(now); entry.Expiration == 0 || expiresIn > 0 {
+			if entry.Expiration == 0 {
+				expiresIn = utilCache.NoExpiration
+			}
+			m.cache.Set(key, entry.Id, expiresIn)
+		}
+	}
+
+	return m, nil
+}
+
+type persistedMetadata struct {
+	Id         int   `json:"id"`
+	Expiration int64 `json:"expiration"`
+}
+
+type fileMetadataCache struct {
+	memoryMetadataCache
+	path  string
+	mutex sync.Mutex
+}
+
+func (m *fileMetadataCache) Set(kind MetadataKind, name string, id int) {
+	m.memoryMetadataCache.Set(kind, name, id)
+	m.save()
+}
+
+func (m *fileMetadataCache) SetAll(kind MetadataKind, ids map[string]int) {
+	m.memoryMetadataCache.SetAll(kind, ids)
+	m.save()
+}
+
+func (m *fileMetadataCache) Invalidate(kinds ...MetadataKind) {
+	m.memoryMetadataCache.Invalidate(kinds...)
+	m.save()
+}
+
+// save writes all entries to a temporary file and renames it, failures are only logged.
+func (m *fileMetadataCache) save() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entries := map[string]persistedMetadata{}
+	for key, item := range m.cache.Items() {
+		entries[key] = persistedMetadata{Id: item.Object.(int), Expiration: item.Expiration}
+	}
+
+	if err := writeFileAtomic(m.path, entries); err != nil {
+		log.Warnf("Failed to save metadata cache %s: %v", m.path, err)
+	}
+}
+
+func writeFileAtomic(path string, value interface{}) (err error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+
+	file, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(file.Name())
+
+	if err = file.Chmod(0600); err != nil {
+		file.Close()
+		return
+	}
+	if _, err = file.Write(content); err != nil {
+		file.Close()
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// SetMetadataCache replaces the cache of metadata ids, e.g. with a `NewFileMetadataCache`.
+func (c *Client) SetMetadataCache(cache MetadataCache) {
+	c.metadataMutex.Lock()
+	defer c.metadataMutex.Unlock()
+
+	c.metadata = cache
+}
+
+// metadataCache returns the cache of metadata ids, an in-memory cache is created on first use.
+func (c *Client) metadataCache() MetadataCache {
+	c.metadataMutex.Lock()
+	defer c.metadataMutex.Unlock()
+
+	if c.metadata == nil {
+		c.metadata = NewMetadataCache(DefaultMetadataCacheTTL)
+	}
+	return c.metadata
+}
+
+// InvalidateMetadata removes the cached ids of the given kinds, all cached ids if no kind is given.
+//
+// Call this after changing the schema by other means than the functions of this client.
+func (c *Client) InvalidateMetadata(kinds ...MetadataKind) {
+	c.metadataCache().Invalidate(kinds...)
+}
+
+type respMetadataList struct {
+	Data []struct {
+		Id   v2.FlexInt `json:"id"`
+		Name string     `json:"name"`
+	} `json:"data"`
+}
+
+// Preload fetches the ids of all ci types, attributes, attribute groups, relation types and projects with one query each.
+//
+// Names returned more than once are not cached, their lookup fails as before.
+func (c *Client) Preload() (err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	var errs utilError.Errors
+	for _, preload := range []struct {
+		kind  MetadataKind
+		query string
+	}{
+		{METADATA_CI_TYPE, QUERY_GET_ALL_CI_TYPES},
+		{METADATA_ATTRIBUTE, QUERY_GET_ALL_ATTRIBUTES},
+		{METADATA_ATTRIBUTE_GROUP, QUERY_GET_ALL_ATTRIBUTE_GROUPS},
+		{METADATA_RELATION_TYPE, QUERY_GET_ALL_RELATION_TYPES},
+		{METADATA_PROJECT, QUERY_GET_ALL_PROJECTS},
+	} {
+		response := respMetadataList{}
+		if queryErr := c.v2.Query(preload.query, &response, map[string]string{}); queryErr != nil {
+			errs = errs.Add(utilError.FunctionError(preload.query + " - " + queryErr.Error()))
+			continue
+		}
+
+		ids := map[string]int{}
+		duplicates := map[string]bool{}
+		for _, row := range response.Data {
+			if _, exists := ids[row.Name]; exists {
+				duplicates[row.Name] = true
+			}
+			ids[row.Name] = row.Id.Int()
+		}
+		for name := range duplicates {
+			delete(ids, name)
+		}
+
+		c.metadataCache().SetAll(preload.kind, ids)
+		log.Debugf("Preloaded %d %s ids", len(ids), preload.kind)
+	}
+
+	if len(errs) > 0 {
+		log.Error("Error: ", errs)
+		return errs
+	}
+	return
+}
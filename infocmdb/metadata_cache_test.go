@@ -0,0 +1,121 @@
+package infocmdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestMetadataCache(t *testing.T) {
+	cache := NewMetadataCache(time.Minute)
+
+	cache.Set(METADATA_CI_TYPE, "demo", 1)
+	cache.Set(METADATA_ATTRIBUTE, "demo", 2)
+	if id, found := cache.Get(METADATA_CI_TYPE, "demo"); !found || id != 1 {
+		t.Errorf("Get() = %v, %v, want 1, true", id, found)
+	}
+
+	cache.SetAll(METADATA_CI_TYPE, map[string]int{"other": 3})
+	if _, found := cache.Get(METADATA_CI_TYPE, "demo"); found {
+		t.Error("Get() found entry replaced by SetAll()")
+	}
+
+	cache.Invalidate(METADATA_CI_TYPE)
+	if _, found := cache.Get(METADATA_CI_TYPE, "other"); found {
+		t.Error("Get() found invalidated entry")
+	}
+	if id, found := cache.Get(METADATA_ATTRIBUTE, "demo"); !found || id != 2 {
+		t.Errorf("Get() of other kind = %v, %v, want 2, true", id, found)
+	}
+
+	cache.Invalidate()
+	if _, found := cache.Get(METADATA_ATTRIBUTE, "demo"); found {
+		t.Error("Get() found entry after invalidating all kinds")
+	}
+}
+
+func TestNewFileMetadataCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "infocmdb-metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "metadata.json")
+
+	cache, err := NewFileMetadataCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileMetadataCache() error = %v", err)
+	}
+	cache.SetAll(METADATA_PROJECT, map[string]int{"springfield": 4})
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("metadata cache file permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := NewFileMetadataCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileMetadataCache() error = %v", err)
+	}
+	if id, found := loaded.Get(METADATA_PROJECT, "springfield"); !found || id != 4 {
+		t.Errorf("Get() of persisted entry = %v, %v, want 4, true", id, found)
+	}
+}
+
+func TestClient_Preload(t *testing.T) {
+	mock := utilTesting.New()
+	for query, data := range map[string]string{
+		QUERY_GET_ALL_CI_TYPES:         `[{"id":"1","name":"demo"},{"id":"2","name":"duplicate"},{"id":"3","name":"duplicate"}]`,
+		QUERY_GET_ALL_ATTRIBUTES:       `[{"id":"10","name":"emp_firstname"}]`,
+		QUERY_GET_ALL_ATTRIBUTE_GROUPS: `[{"id":"20","name":"general"}]`,
+		QUERY_GET_ALL_RELATION_TYPES:   `[{"id":"30","name":"depends_on"}]`,
+		QUERY_GET_ALL_PROJECTS:         `[{"id":"4","name":"springfield"}]`,
+	} {
+		mock.AddMocking(utilTesting.Mocking{
+			RequestString: `PUT##/apiV2/query/execute/` + query + `##{"query":{"params":{}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":` + data + `}`,
+		})
+	}
+
+	cmdbV2 := v2.New()
+	cmdbV2.LoadConfig(v2.Config{Url: mock.GetUrl(), Username: "admin", Password: "admin"})
+	cmdb := &Client{v2: cmdbV2}
+
+	if err := cmdb.Preload(); err != nil {
+		t.Fatalf("Preload() error = %v", err)
+	}
+
+	// lookups are answered from the cache, no query is mocked for them
+	tests := []struct {
+		name   string
+		lookup func(string) (int, error)
+		arg    string
+		want   int
+	}{
+		{"ci type", cmdb.GetCiTypeIdByCiTypeName, "demo", 1},
+		{"attribute", cmdb.GetAttributeIdByAttributeName, "emp_firstname", 10},
+		{"attribute group", cmdb.GetAttributeGroupIdByName, "general", 20},
+		{"relation type", cmdb.GetCiRelationTypeIdByRelationTypeName, "depends_on", 30},
+		{"project", cmdb.GetProjectIdByProjectName, "springfield", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.lookup(tt.arg)
+			if err != nil || got != tt.want {
+				t.Errorf("lookup(%s) = %v, %v, want %v", tt.arg, got, err, tt.want)
+			}
+		})
+	}
+
+	if _, found := cmdb.metadataCache().Get(METADATA_CI_TYPE, "duplicate"); found {
+		t.Error("Preload() cached a duplicate name")
+	}
+}
@@ -3,49 +3,31 @@ package infocmdb
 import (
 	"strconv"
 
-	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
-	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
-	utilCache "github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 )
 
 type getProjectIdByProjectName struct {
-	Data []responseId `json:"data"`
+	Name string `argv:"1"`
 }
 
+func (getProjectIdByProjectName) QueryName() string { return "int_getProjectIdByProjectName" }
+
 func (c *Client) GetProjectIdByProjectName(name string) (projectID int, err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
 
-	cacheKey := "GetProjectIdByProjectName_" + name
-	cached, found := c.v2.Cache.Get(cacheKey)
-	if found {
-		return cached.(int), nil
-	}
-
-	params := map[string]string{
-		"argv1": name,
+	if cached, found := c.metadataCache().Get(METADATA_PROJECT, name); found {
+		return cached, nil
 	}
 
-	response := getProjectIdByProjectName{}
-	err = c.v2.Query("int_getProjectIdByProjectName", &response, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
+	row := responseId{}
+	if err = c.queryOne(getProjectIdByProjectName{Name: name}, &row, name); err != nil {
 		return
 	}
 
-	switch len(response.Data) {
-	case 0:
-		err = utilError.FunctionError(name + " - " + v2.ErrNoResult.Error())
-	case 1:
-		projectID = response.Data[0].Id
-		c.v2.Cache.Set(cacheKey, projectID, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(name + " - " + v2.ErrTooManyResults.Error())
-	}
-
+	projectID = row.Id.Int()
+	c.metadataCache().Set(METADATA_PROJECT, name, projectID)
 	return
 }
 
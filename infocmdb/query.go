@@ -2,12 +2,14 @@ package infocmdb
 
 import (
 	log "github.com/sirupsen/logrus"
+
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 )
 
 // QueryWebservices allows you to call a generic webservice(arg1: ws) with the providing params
 // Return: json string
 func (c *Client) QueryWebservice(ws string, params map[string]string) (resp string, err error) {
-	log.Debugf("Querying webservice %v with params %v", ws, params)
+	log.Debugf("Querying webservice %v with params %v", ws, redact.Params(params))
 
 	if err = c.v2.Login(); err != nil {
 		return
@@ -18,7 +20,9 @@ func (c *Client) QueryWebservice(ws string, params map[string]string) (resp stri
 		log.Error("Error: ", err)
 	}
 
-	log.Debugf("Result: %v", resp)
+	if log.IsLevelEnabled(log.DebugLevel) {
+		log.Debugf("Result: %v", redact.String(resp))
+	}
 	return
 }
 
@@ -26,7 +30,7 @@ func (c *Client) QueryWebservice(ws string, params map[string]string) (resp stri
 // to a result. It will take the built in resty function to deserialize the result
 // Return: error
 func (c *Client) Query(ws string, out interface{}, params map[string]string) (err error) {
-	log.Debugf("Querying webservice %v with params %v", ws, params)
+	log.Debugf("Querying webservice %v with params %v", ws, redact.Params(params))
 
 	if err = c.v2.Login(); err != nil {
 		return
@@ -36,6 +40,8 @@ func (c *Client) Query(ws string, out interface{}, params map[string]string) (er
 		log.Error("Error: ", err)
 	}
 
-	log.Debugf("Result: %v", out)
+	if log.IsLevelEnabled(log.DebugLevel) {
+		log.Debugf("Result: %s", redact.Struct(out))
+	}
 	return
 }
@@ -4,8 +4,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
-	utilCache "github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
@@ -16,6 +16,16 @@ type createCiRelation struct {
 }
 
 func (c *Client) CreateCiRelation(ciId1 int, ciId2 int, ciRelationTypeName string, direction v2.CiRelationDirection) (err error) {
+	return c.createCiRelation(ciId1, ciId2, ciRelationTypeName, direction, nil)
+}
+
+// CreateCiRelationWithAttributes creates the relation with a weighting, color and note.
+// An existing relation between the cis is not changed, use `UpdateCiRelation` for it.
+func (c *Client) CreateCiRelationWithAttributes(ciId1 int, ciId2 int, ciRelationTypeName string, direction v2.CiRelationDirection, attributes RelationAttributes) (err error) {
+	return c.createCiRelation(ciId1, ciId2, ciRelationTypeName, direction, &attributes)
+}
+
+func (c *Client) createCiRelation(ciId1 int, ciId2 int, ciRelationTypeName string, direction v2.CiRelationDirection, attributes *RelationAttributes) (err error) {
 	if err = c.v2.Login(); err != nil {
 		return
 	}
@@ -33,27 +43,41 @@ func (c *Client) CreateCiRelation(ciId1 int, ciId2 int, ciRelationTypeName strin
 	}
 
 	if counter == 0 {
-		var ciRelationTypeId int
-		ciRelationTypeId, err = c.GetCiRelationTypeIdByRelationTypeName(ciRelationTypeName)
-		if err != nil {
-			err = utilError.FunctionError(err.Error())
-			return
-		}
+		err = c.insertCiRelation(ciId1, ciId2, ciRelationTypeName, directionId, attributes)
+	}
 
-		params := map[string]string{
-			"argv1": strconv.Itoa(ciId1),
-			"argv2": strconv.Itoa(ciId2),
-			"argv3": strconv.Itoa(ciRelationTypeId),
-			"argv4": strconv.Itoa(directionId),
-		}
+	return
+}
 
-		jsonRet := createCiRelation{}
-		err = c.v2.Query("int_createCiRelation", &jsonRet, params)
-		if err != nil {
-			err = utilError.FunctionError(err.Error())
-			log.Error("Error: ", err)
-			return
-		}
+// insertCiRelation creates the relation without checking for existing relations between the cis.
+func (c *Client) insertCiRelation(ciId1 int, ciId2 int, ciRelationTypeName string, directionId int, attributes *RelationAttributes) (err error) {
+	ciRelationTypeId, err := c.GetCiRelationTypeIdByRelationTypeName(ciRelationTypeName)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		return
+	}
+
+	params := map[string]string{
+		"argv1": strconv.Itoa(ciId1),
+		"argv2": strconv.Itoa(ciId2),
+		"argv3": strconv.Itoa(ciRelationTypeId),
+		"argv4": strconv.Itoa(directionId),
+	}
+
+	query := "int_createCiRelation"
+	if attributes != nil {
+		query = "int_createCiRelationWithAttributes"
+		params["argv5"] = strconv.Itoa(attributes.Weighting)
+		params["argv6"] = attributes.Color
+		params["argv7"] = attributes.Note
+	}
+
+	jsonRet := createCiRelation{}
+	err = c.v2.Query(query, &jsonRet, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
 	}
 
 	return
@@ -90,8 +114,32 @@ func (c *Client) DeleteCiRelation(ciId1 int, ciId2 int, ciRelationTypeName strin
 	return
 }
 
+// DeleteCiRelationById deletes the relation with the id.
+//
+// Unlike `DeleteCiRelation`, which deletes the relations of the type between the cis in both directions,
+// other relations between the cis are kept.
+func (c *Client) DeleteCiRelationById(relationId int) (err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	params := map[string]string{
+		"argv1": strconv.Itoa(relationId),
+	}
+
+	jsonRet := deleteCiRelation{}
+	err = c.v2.Query("int_deleteCiRelationById", &jsonRet, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	return
+}
+
 func (c *Client) AttributeBasedRelation(sourceCiId int, attributeName string, ciRelationTypeName string, triggerType string, swapCiColumns bool) (relationCisAdded []int, relationCisRemoved []int, err error) {
-	var currentCiValues []string
+	var destinationCiIds []int
 	if triggerType != "ci_attribute_delete" {
 		var value string
 		value, _, err = c.GetCiAttributeValueCi(sourceCiId, attributeName)
@@ -99,20 +147,34 @@ func (c *Client) AttributeBasedRelation(sourceCiId int, attributeName string, ci
 			if !strings.Contains(err.Error(), v2.ErrNoResult.Error()) {
 				return
 			}
-		} else if value != "" {
-			currentCiValues = regexp.MustCompile(`,\s?`).Split(value, -1)
+		} else {
+			destinationCiIds = parseValueCiIds(value)
 		}
 	}
 
-	destinationCiIds := make([]int, len(currentCiValues))
-	for i, s := range currentCiValues {
-		destinationCiIds[i], _ = strconv.Atoi(strings.TrimSpace(s))
-	}
-
 	return c.CiBasedRelation(sourceCiId, destinationCiIds, ciRelationTypeName, triggerType, swapCiColumns)
 }
 
-//noinspection GoUnusedParameter preserved to remain backwards compatible
+// parseValueCiIds returns the ci ids of a comma separated value of a ci attribute, values that are no ids are skipped.
+func parseValueCiIds(value string) (ciIds []int) {
+	if value == "" {
+		return
+	}
+	for _, s := range regexp.MustCompile(`,\s?`).Split(value, -1) {
+		ciId, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Debugf("Skipping value \"%s\", it is no ci id", s)
+			continue
+		}
+		ciIds = append(ciIds, ciId)
+	}
+	return
+}
+
+// CiBasedRelation replaces the relations of the source ci with omnidirectional relations to the given cis.
+// Use `ReconcileRelations` to respect the direction of the relations.
+//
+// noinspection GoUnusedParameter preserved to remain backwards compatible
 func (c *Client) CiBasedRelation(srcCiId int, destCiId []int, ciRelationTypeName string, triggerType string, swapCiColumns bool) (relationCisAdded []int, relationCisRemoved []int, err error) {
 	currentCiRelations, err := c.GetListOfCiIdsByCiRelation(srcCiId, ciRelationTypeName, v2.CI_RELATION_DIRECTION_ALL)
 	if err != nil {
@@ -167,9 +229,15 @@ func (c *Client) CiBasedRelation(srcCiId int, destCiId []int, ciRelationTypeName
 }
 
 type getCiRelationCount struct {
-	Data []struct {
-		Count int `json:"c,string"`
-	} `json:"data"`
+	CiId1          int `argv:"1"`
+	CiId2          int `argv:"2"`
+	RelationTypeId int `argv:"3"`
+}
+
+func (getCiRelationCount) QueryName() string { return "int_getCiRelationCount" }
+
+type ciRelationCountRow struct {
+	Count int `json:"c"`
 }
 
 func (c *Client) GetCiRelationCount(ciId1 int, ciId2 int, ciRelationTypeName string) (r int, err error) {
@@ -183,91 +251,182 @@ func (c *Client) GetCiRelationCount(ciId1 int, ciId2 int, ciRelationTypeName str
 		return
 	}
 
-	params := map[string]string{
-		"argv1": strconv.Itoa(ciId1),
-		"argv2": strconv.Itoa(ciId2),
-		"argv3": strconv.Itoa(ciRelationTypeId),
+	subject := strconv.Itoa(ciId1) + ", " + strconv.Itoa(ciId2) + ", " + ciRelationTypeName + "(" + strconv.Itoa(ciRelationTypeId) + ")"
+	row := ciRelationCountRow{}
+	err = c.queryOne(getCiRelationCount{CiId1: ciId1, CiId2: ciId2, RelationTypeId: ciRelationTypeId}, &row, subject)
+	if err != nil {
+		return
 	}
 
-	jsonRet := getCiRelationCount{}
-	err = c.v2.Query("int_getCiRelationCount", &jsonRet, params)
-	if err != nil {
-		err = utilError.FunctionError(err.Error())
-		log.Error("Error: ", err)
-		return r, err
+	return row.Count, nil
+}
+
+type getCiRelationTypeIdByRelationTypeName struct {
+	Name string `argv:"1"`
+}
+
+func (getCiRelationTypeIdByRelationTypeName) QueryName() string {
+	return "int_getCiRelationTypeIdByRelationTypeName"
+}
+
+func (c *Client) GetCiRelationTypeIdByRelationTypeName(name string) (r int, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
 	}
 
-	errPrefix := strconv.Itoa(ciId1) + ", " + strconv.Itoa(ciId2) + ", " + ciRelationTypeName + "(" + strconv.Itoa(ciRelationTypeId) + ")" + " - "
-	switch len(jsonRet.Data) {
-	case 0:
-		err = utilError.FunctionError(errPrefix + v2.ErrNoResult.Error())
-	case 1:
-		r = jsonRet.Data[0].Count
-	default:
-		err = utilError.FunctionError(errPrefix + v2.ErrTooManyResults.Error())
+	if cached, found := c.metadataCache().Get(METADATA_RELATION_TYPE, name); found {
+		return cached, nil
 	}
 
+	row := responseId{}
+	if err = c.queryOne(getCiRelationTypeIdByRelationTypeName{Name: name}, &row, name); err != nil {
+		return
+	}
+
+	r = row.Id.Int()
+	c.metadataCache().Set(METADATA_RELATION_TYPE, name, r)
 	return
+}
 
+type Relation struct {
+	Id               int
+	CiId1            int
+	CiId2            int
+	Direction        v2.CiRelationDirection
+	RelationTypeId   int
+	RelationTypeName string
+	Weighting        int
+	Color            string
+	Note             string
+	// User who created the relation and the time it was created
+	UserId    int
+	ValidFrom time.Time
 }
 
-type getCiRelationTypeIdByRelationTypeName struct {
-	Data []responseId `json:"data"`
+// RelationAttributes are the optional values of a relation.
+type RelationAttributes struct {
+	Weighting int
+	// Hex color, e.g. "FF0000"
+	Color string
+	Note  string
 }
 
-func (c *Client) GetCiRelationTypeIdByRelationTypeName(name string) (r int, err error) {
-	if err = c.v2.Login(); err != nil {
-		return
+type relationRow struct {
+	Id               v2.FlexInt    `json:"id"`
+	CiId1            v2.FlexInt    `json:"ci_id_1"`
+	CiId2            v2.FlexInt    `json:"ci_id_2"`
+	Direction        v2.FlexInt    `json:"direction"`
+	RelationTypeId   v2.FlexInt    `json:"ci_relation_type_id"`
+	RelationTypeName string        `json:"ci_relation_type_name"`
+	Weighting        v2.FlexInt    `json:"weighting"`
+	Color            v2.NullString `json:"color"`
+	Note             v2.NullString `json:"note"`
+	UserId           v2.FlexInt    `json:"user_id"`
+	ValidFrom        v2.FlexTime   `json:"valid_from"`
+}
+
+func (row relationRow) relation() (relation Relation, err error) {
+	// relations of old infoCMDB versions have direction 0, they are treated as omnidirectional
+	direction := v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL
+	if row.Direction.Int() != 0 {
+		if direction, err = v2.NewCiRelationDirection(row.Direction.Int()); err != nil {
+			return
+		}
 	}
 
-	cacheKey := "GetCiRelationTypeIdByRelationTypeName_" + name
-	cached, found := c.v1.Cache.Get(cacheKey)
-	if found {
-		return cached.(int), nil
+	return Relation{
+		Id:               row.Id.Int(),
+		CiId1:            row.CiId1.Int(),
+		CiId2:            row.CiId2.Int(),
+		Direction:        direction,
+		RelationTypeId:   row.RelationTypeId.Int(),
+		RelationTypeName: row.RelationTypeName,
+		Weighting:        row.Weighting.Int(),
+		Color:            row.Color.String,
+		Note:             row.Note.String,
+		UserId:           row.UserId.Int(),
+		ValidFrom:        row.ValidFrom.Time,
+	}, nil
+}
+
+type getCiRelationsByName struct {
+	Data []relationRow `json:"data"`
+}
+
+func (c *Client) GetListOfRelationsByName(name string) (relations []Relation, err error) {
+	relations = []Relation{}
+
+	if err = c.v2.Login(); err != nil {
+		return
 	}
 
 	params := map[string]string{
 		"argv1": name,
 	}
 
-	jsonRet := getCiRelationTypeIdByRelationTypeName{}
-	err = c.v2.Query("int_getCiRelationTypeIdByRelationTypeName", &jsonRet, params)
+	jsonRet := getCiRelationsByName{}
+	err = c.v2.Query("int_getCiRelationsByName", &jsonRet, params)
 	if err != nil {
 		err = utilError.FunctionError(err.Error())
 		log.Error("Error: ", err)
 		return
 	}
 
-	switch len(jsonRet.Data) {
-	case 0:
-		err = utilError.FunctionError(name + " - " + v2.ErrNoResult.Error())
-	case 1:
-		r = jsonRet.Data[0].Id
-		c.v1.Cache.Set(cacheKey, r, utilCache.DefaultExpiration)
-	default:
-		err = utilError.FunctionError(name + " - " + v2.ErrTooManyResults.Error())
+	for _, row := range jsonRet.Data {
+		relation, err := row.relation()
+		if err != nil {
+			return relations, err
+		}
+		if relation.RelationTypeName == "" {
+			relation.RelationTypeName = name
+		}
+
+		relations = append(relations, relation)
 	}
 
 	return
 }
 
-type Relation struct {
-	Id        int
-	CiId1     int
-	CiId2     int
-	Direction v2.CiRelationDirection
+type updateCiRelation struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
 }
 
-type getCiRelationsByName struct {
-	Data []struct {
-		Id        IntWrapper `json:"id"`
-		CiId1     IntWrapper `json:"ci_id_1"`
-		CiId2     IntWrapper `json:"ci_id_2"`
-		Direction IntWrapper `json:"direction"`
-	} `json:"data"`
+// UpdateCiRelation sets the weighting, color and note of the relation.
+func (c *Client) UpdateCiRelation(relationId int, attributes RelationAttributes) (err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	params := map[string]string{
+		"argv1": strconv.Itoa(relationId),
+		"argv2": strconv.Itoa(attributes.Weighting),
+		"argv3": attributes.Color,
+		"argv4": attributes.Note,
+	}
+
+	jsonRet := updateCiRelation{}
+	err = c.v2.Query("int_updateCiRelation", &jsonRet, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	if !jsonRet.Success {
+		err = utilError.FunctionError(strconv.Itoa(relationId) + " - couldn't update relation: " + jsonRet.Message)
+		log.Error("Error: ", err)
+	}
+
+	return
 }
 
-func (c *Client) GetListOfRelationsByName(name string) (relations []Relation, err error) {
+type getCiRelations struct {
+	Data []relationRow `json:"data"`
+}
+
+// GetCiRelations returns all relations of the ci, of every relation type and in both columns.
+func (c *Client) GetCiRelations(ciId int) (relations []Relation, err error) {
 	relations = []Relation{}
 
 	if err = c.v2.Login(); err != nil {
@@ -275,30 +434,47 @@ func (c *Client) GetListOfRelationsByName(name string) (relations []Relation, er
 	}
 
 	params := map[string]string{
-		"argv1": name,
+		"argv1": strconv.Itoa(ciId),
 	}
 
-	jsonRet := getCiRelationsByName{}
-	err = c.v2.Query("int_getCiRelationsByName", &jsonRet, params)
+	jsonRet := getCiRelations{}
+	err = c.v2.Query("int_getCiRelations", &jsonRet, params)
 	if err != nil {
 		err = utilError.FunctionError(err.Error())
 		log.Error("Error: ", err)
 		return
 	}
 
-	for _, relation := range jsonRet.Data {
-		direction, err := v2.NewCiRelationDirection(int(relation.Direction))
+	for _, row := range jsonRet.Data {
+		relation, err := row.relation()
 		if err != nil {
-		    return relations, err
+			return relations, err
 		}
-
-		relations = append(relations, Relation{
-			Id:        int(relation.Id),
-			CiId1:     int(relation.CiId1),
-			CiId2:     int(relation.CiId2),
-			Direction: direction,
-		})
+		relations = append(relations, relation)
 	}
 
 	return
 }
+
+// OtherCiId returns the id of the related ci, seen from the given ci.
+func (r Relation) OtherCiId(ciId int) int {
+	if r.CiId1 == ciId {
+		return r.CiId2
+	}
+	return r.CiId1
+}
+
+// DirectionFrom returns the direction of the relation seen from the given ci,
+// e.g. CI_RELATION_DIRECTION_DIRECTED_TO if it points from the ci to the other ci.
+func (r Relation) DirectionFrom(ciId int) v2.CiRelationDirection {
+	if r.CiId1 == ciId {
+		return r.Direction
+	}
+	switch r.Direction {
+	case v2.CI_RELATION_DIRECTION_DIRECTED_TO:
+		return v2.CI_RELATION_DIRECTION_DIRECTED_FROM
+	case v2.CI_RELATION_DIRECTION_DIRECTED_FROM:
+		return v2.CI_RELATION_DIRECTION_DIRECTED_TO
+	}
+	return r.Direction
+}
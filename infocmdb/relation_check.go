@@ -0,0 +1,282 @@
+package infocmdb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type RelationProblemKind string
+
+const (
+	// One of the related cis doesn't exist anymore
+	RELATION_PROBLEM_DELETED_CI RelationProblemKind = "deleted_ci"
+	// The ci types of the related cis are not part of `RelationCheckOptions.AllowedCiTypes`
+	RELATION_PROBLEM_DISALLOWED_CI_TYPES RelationProblemKind = "disallowed_ci_types"
+	// Another relation of the same type between the same cis exists
+	RELATION_PROBLEM_DUPLICATE RelationProblemKind = "duplicate"
+	// A value of a ci attribute has no relation (see `AttributeBasedRelation`)
+	RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION RelationProblemKind = "missing_attribute_relation"
+)
+
+// RelationCiTypes is a pair of ci types that may be related, independent of the column of the ci.
+type RelationCiTypes struct {
+	CiType1 string
+	CiType2 string
+}
+
+// AttributeRelationRule declares that the values of a ci attribute are kept as relations,
+// with the same parameters as `AttributeBasedRelation`.
+type AttributeRelationRule struct {
+	CiType        string
+	Attribute     string
+	SwapCiColumns bool
+}
+
+type RelationCheckOptions struct {
+	// Allowed ci type pairs, the check is skipped if empty
+	AllowedCiTypes []RelationCiTypes
+	// Ci attributes whose values must be related with the checked relation type
+	AttributeRelations []AttributeRelationRule
+	// Delete relations to deleted cis, remove duplicates and create missing attribute relations.
+	// Relations between disallowed ci types are only reported, they need a decision which ci is wrong.
+	Fix bool
+}
+
+type RelationProblem struct {
+	Kind RelationProblemKind
+	// The relation with the problem, for missing relations the relation that is expected
+	Relation Relation
+	Message  string
+	Fixed    bool
+}
+
+type RelationCheckReport struct {
+	RelationType string
+	// Number of checked relations
+	Checked  int
+	Problems []RelationProblem
+}
+
+// CheckRelations checks the consistency of all relations of the given type, e.g. in a nightly workflow.
+//
+// Problems of the relations are reported in the order of the relation ids. With `Fix` the fixable problems
+// are repaired, failed fixes don't stop the check and are returned together.
+func (c *Client) CheckRelations(relationTypeName string, opts RelationCheckOptions) (report RelationCheckReport, err error) {
+	report.RelationType = relationTypeName
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	relations, err := c.GetListOfRelationsByName(relationTypeName)
+	if err != nil {
+		return
+	}
+	sort.Slice(relations, func(i, j int) bool { return relations[i].Id < relations[j].Id })
+	report.Checked = len(relations)
+
+	ciTypes, err := c.ciTypesOfRelations(relations)
+	if err != nil {
+		return
+	}
+
+	allowed := map[string]bool{}
+	for _, pair := range opts.AllowedCiTypes {
+		allowed[ciTypePairKey(pair.CiType1, pair.CiType2)] = true
+	}
+
+	// first relation between each pair of cis
+	related := map[string]Relation{}
+	for _, relation := range relations {
+		ciType1, exists1 := ciTypes[relation.CiId1]
+		ciType2, exists2 := ciTypes[relation.CiId2]
+		if !exists1 || !exists2 {
+			deleted := relation.CiId1
+			if exists1 {
+				deleted = relation.CiId2
+			}
+			report.Problems = append(report.Problems, RelationProblem{
+				Kind:     RELATION_PROBLEM_DELETED_CI,
+				Relation: relation,
+				Message:  fmt.Sprintf("relation %d: ci %d doesn't exist", relation.Id, deleted),
+			})
+			continue
+		}
+
+		pair := ciPairKey(relation.CiId1, relation.CiId2)
+		if _, duplicate := related[pair]; duplicate {
+			report.Problems = append(report.Problems, RelationProblem{
+				Kind:     RELATION_PROBLEM_DUPLICATE,
+				Relation: relation,
+				Message:  fmt.Sprintf("relation %d: cis %d and %d are already related", relation.Id, relation.CiId1, relation.CiId2),
+			})
+			continue
+		}
+		related[pair] = relation
+
+		if len(allowed) > 0 && !allowed[ciTypePairKey(ciType1, ciType2)] {
+			report.Problems = append(report.Problems, RelationProblem{
+				Kind:     RELATION_PROBLEM_DISALLOWED_CI_TYPES,
+				Relation: relation,
+				Message:  fmt.Sprintf("relation %d: %s %d and %s %d may not be related", relation.Id, ciType1, relation.CiId1, ciType2, relation.CiId2),
+			})
+		}
+	}
+
+	for _, rule := range opts.AttributeRelations {
+		var missing []RelationProblem
+		if missing, err = c.missingAttributeRelations(relationTypeName, rule, related); err != nil {
+			return
+		}
+		report.Problems = append(report.Problems, missing...)
+	}
+
+	if !opts.Fix {
+		return
+	}
+
+	return report, c.fixRelations(relationTypeName, &report)
+}
+
+// ciTypesOfRelations returns the ci type names of the related cis, deleted cis are missing.
+func (c *Client) ciTypesOfRelations(relations []Relation) (ciTypes map[int]string, err error) {
+	ciTypes = map[int]string{}
+	checked := map[int]bool{}
+	for _, relation := range relations {
+		for _, ciId := range []int{relation.CiId1, relation.CiId2} {
+			if checked[ciId] {
+				continue
+			}
+			checked[ciId] = true
+
+			ciType, err := c.GetCiTypeName(ciId)
+			if err != nil {
+				if strings.Contains(err.Error(), v2.ErrNoResult.Error()) {
+					continue
+				}
+				return nil, err
+			}
+			ciTypes[ciId] = ciType
+		}
+	}
+	return
+}
+
+// missingAttributeRelations returns the values of the attribute of all cis of the rule's type that are not related.
+func (c *Client) missingAttributeRelations(relationTypeName string, rule AttributeRelationRule, related map[string]Relation) (problems []RelationProblem, err error) {
+	ciIds, err := c.GetListOfCiIdsOfCiTypeName(rule.CiType)
+	if err != nil {
+		return
+	}
+
+	for _, ciId := range ciIds {
+		value, _, err := c.GetCiAttributeValueCi(ciId, rule.Attribute)
+		if err != nil {
+			if strings.Contains(err.Error(), v2.ErrNoResult.Error()) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, valueCiId := range parseValueCiIds(value) {
+			if _, found := related[ciPairKey(ciId, valueCiId)]; found {
+				continue
+			}
+
+			relation := Relation{CiId1: ciId, CiId2: valueCiId, Direction: v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL, RelationTypeName: relationTypeName}
+			if rule.SwapCiColumns {
+				relation.CiId1, relation.CiId2 = valueCiId, ciId
+			}
+			problems = append(problems, RelationProblem{
+				Kind:     RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION,
+				Relation: relation,
+				Message:  fmt.Sprintf("%s %d: %s %d is not related", rule.CiType, ciId, rule.Attribute, valueCiId),
+			})
+		}
+	}
+	return
+}
+
+// fixRelations deletes relations by id, so the first of duplicate relations is kept unchanged.
+func (c *Client) fixRelations(relationTypeName string, report *RelationCheckReport) error {
+	var errs utilError.Errors
+
+	for i, problem := range report.Problems {
+		relation := problem.Relation
+		var err error
+		switch problem.Kind {
+		case RELATION_PROBLEM_DELETED_CI, RELATION_PROBLEM_DUPLICATE:
+			err = c.DeleteCiRelationById(relation.Id)
+		case RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION:
+			err = c.CreateCiRelation(relation.CiId1, relation.CiId2, relationTypeName, relation.Direction)
+		default:
+			continue
+		}
+
+		if err != nil {
+			errs = errs.Add(err)
+			continue
+		}
+		report.Problems[i].Fixed = true
+	}
+
+	if len(errs) > 0 {
+		log.Error("Error: ", errs)
+		return errs
+	}
+	return nil
+}
+
+func ciPairKey(ciId1 int, ciId2 int) string {
+	if ciId1 > ciId2 {
+		ciId1, ciId2 = ciId2, ciId1
+	}
+	return strconv.Itoa(ciId1) + "-" + strconv.Itoa(ciId2)
+}
+
+func ciTypePairKey(ciType1 string, ciType2 string) string {
+	if ciType1 > ciType2 {
+		ciType1, ciType2 = ciType2, ciType1
+	}
+	return ciType1 + "\n" + ciType2
+}
+
+// ProblemsOfKind returns the problems of the given kind.
+func (r RelationCheckReport) ProblemsOfKind(kind RelationProblemKind) (problems []RelationProblem) {
+	for _, problem := range r.Problems {
+		if problem.Kind == kind {
+			problems = append(problems, problem)
+		}
+	}
+	return
+}
+
+// String returns a summary of the report, e.g. for logging.
+func (r RelationCheckReport) String() string {
+	fixed := 0
+	for _, problem := range r.Problems {
+		if problem.Fixed {
+			fixed++
+		}
+	}
+
+	counts := make([]string, 0, 4)
+	for _, kind := range []RelationProblemKind{
+		RELATION_PROBLEM_DELETED_CI,
+		RELATION_PROBLEM_DISALLOWED_CI_TYPES,
+		RELATION_PROBLEM_DUPLICATE,
+		RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION,
+	} {
+		counts = append(counts, strconv.Itoa(len(r.ProblemsOfKind(kind)))+" "+string(kind))
+	}
+
+	return r.RelationType + ": " + strconv.Itoa(r.Checked) + " relations checked, " + strings.Join(counts, ", ") +
+		", " + strconv.Itoa(fixed) + " fixed"
+}
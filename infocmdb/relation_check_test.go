@@ -0,0 +1,138 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_CheckRelations(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	mockings := []utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationsByName##{"query":{"params":{"argv1":"depends_on"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"4","ci_id_1":"44","ci_id_2":"45","direction":"4","ci_relation_type_id":"30"},` +
+				`{"id":"1","ci_id_1":"42","ci_id_2":"43","direction":"4","ci_relation_type_id":"30"},` +
+				`{"id":"2","ci_id_1":"43","ci_id_2":"42","direction":"4","ci_relation_type_id":"30"},` +
+				`{"id":"3","ci_id_1":"42","ci_id_2":"99","direction":"4","ci_relation_type_id":"30"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiTypeOfCi##{"query":{"params":{"argv1":"99","argv2":"name"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getListOfCiIdsOfCiType##{"query":{"params":{"argv1":"12"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ciid":"43"},{"ciid":"44"},{"ciid":"45"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributeValue##{"query":{"params":{"argv1":"43","argv2":"70","argv3":"value_ci"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"500","v":"42"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributeValue##{"query":{"params":{"argv1":"44","argv2":"70","argv3":"value_ci"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"501","v":"42"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributeValue##{"query":{"params":{"argv1":"45","argv2":"70","argv3":"value_ci"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"502","v":"n/a"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCiRelationById##{"query":{"params":{"argv1":"2"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCiRelationById##{"query":{"params":{"argv1":"3"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"44","argv2":"42","argv3":"30"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelation##{"query":{"params":{"argv1":"44","argv2":"42","argv3":"30","argv4":"4"}}}`,
+			ReturnString:  emptyResult,
+		},
+	}
+	for ciId, ciType := range map[string]string{"42": "server", "43": "application", "44": "application", "45": "application"} {
+		mockings = append(mockings, utilTesting.Mocking{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiTypeOfCi##{"query":{"params":{"argv1":"` + ciId + `","argv2":"name"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"` + ciType + `"}]}`,
+		})
+	}
+
+	cmdb := newTestClient(mockings)
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "depends_on", 30)
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "application", 12)
+	cmdb.metadataCache().Set(METADATA_ATTRIBUTE, "runs_on_server", 70)
+
+	opts := RelationCheckOptions{
+		AllowedCiTypes:     []RelationCiTypes{{CiType1: "application", CiType2: "server"}},
+		AttributeRelations: []AttributeRelationRule{{CiType: "application", Attribute: "runs_on_server"}},
+	}
+
+	tests := []struct {
+		name       string
+		fix        bool
+		wantKinds  []RelationProblemKind
+		wantFixed  []bool
+		wantString string
+	}{
+		{
+			name: "check",
+			wantKinds: []RelationProblemKind{
+				RELATION_PROBLEM_DUPLICATE,
+				RELATION_PROBLEM_DELETED_CI,
+				RELATION_PROBLEM_DISALLOWED_CI_TYPES,
+				RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION,
+			},
+			wantFixed:  []bool{false, false, false, false},
+			wantString: "depends_on: 4 relations checked, 1 deleted_ci, 1 disallowed_ci_types, 1 duplicate, 1 missing_attribute_relation, 0 fixed",
+		},
+		{
+			name: "fix",
+			fix:  true,
+			wantKinds: []RelationProblemKind{
+				RELATION_PROBLEM_DUPLICATE,
+				RELATION_PROBLEM_DELETED_CI,
+				RELATION_PROBLEM_DISALLOWED_CI_TYPES,
+				RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION,
+			},
+			wantFixed:  []bool{true, true, false, true},
+			wantString: "depends_on: 4 relations checked, 1 deleted_ci, 1 disallowed_ci_types, 1 duplicate, 1 missing_attribute_relation, 3 fixed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts.Fix = tt.fix
+			got, err := cmdb.CheckRelations("depends_on", opts)
+			if err != nil {
+				t.Fatalf("CheckRelations() error = %v", err)
+			}
+
+			var kinds []RelationProblemKind
+			var fixed []bool
+			for _, problem := range got.Problems {
+				kinds = append(kinds, problem.Kind)
+				fixed = append(fixed, problem.Fixed)
+			}
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Errorf("CheckRelations() kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			if !reflect.DeepEqual(fixed, tt.wantFixed) {
+				t.Errorf("CheckRelations() fixed = %v, want %v", fixed, tt.wantFixed)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("String() got = %v, want %v", got.String(), tt.wantString)
+			}
+
+			missing := got.ProblemsOfKind(RELATION_PROBLEM_MISSING_ATTRIBUTE_RELATION)
+			wantMissing := Relation{CiId1: 44, CiId2: 42, Direction: v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL, RelationTypeName: "depends_on"}
+			if len(missing) != 1 || !reflect.DeepEqual(missing[0].Relation, wantMissing) {
+				t.Errorf("missing attribute relations = %+v, want %+v", missing, wantMissing)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package infocmdb
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+)
+
+// RelationGraph is a subgraph of cis and their relations that can be exported as DOT, GraphML or JSON.
+type RelationGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	CiId   int    `json:"id"`
+	CiType string `json:"ciType"`
+	// Value of the label attribute, the ci id if it has no value
+	Label string `json:"label"`
+}
+
+// GraphEdge is a relation between two cis.
+//
+// Directed relations point from From to To, bidirectional, omnidirectional and relations of
+// unknown direction (CI_RELATION_DIRECTION_ALL) are exported as undirected edges.
+type GraphEdge struct {
+	From         int                    `json:"from"`
+	To           int                    `json:"to"`
+	RelationType string                 `json:"relationType"`
+	Direction    v2.CiRelationDirection `json:"direction"`
+}
+
+// Directed returns true if the relation points from From to To.
+func (e GraphEdge) Directed() bool {
+	return e.Direction == v2.CI_RELATION_DIRECTION_DIRECTED_TO
+}
+
+// NewRelationGraph returns the graph of the cis and relations reached by a traversal.
+// Nodes are labelled with their ci type and the first value of labelAttribute,
+// edges have the direction of the relations (read with `GetCiRelations`).
+func (c *Client) NewRelationGraph(result TraversalResult, labelAttribute string) (graph RelationGraph, err error) {
+	ciIds := result.CiIds()
+	attributes, err := c.GetMapOfCiAttributes(ciIds)
+	if err != nil {
+		return
+	}
+
+	for _, ciId := range ciIds {
+		node := GraphNode{CiId: ciId, Label: strconv.Itoa(ciId)}
+		if node.CiType, err = c.GetCiTypeName(ciId); err != nil {
+			return
+		}
+		for _, attribute := range attributes[ciId] {
+			if attribute.AttributeName == labelAttribute && attribute.Value != "" {
+				node.Label = attribute.Value
+				break
+			}
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	relationsOfCi := map[int][]Relation{}
+	for _, edge := range result.Edges {
+		relations, cached := relationsOfCi[edge.From]
+		if !cached {
+			if relations, err = c.GetCiRelations(edge.From); err != nil {
+				return
+			}
+			relationsOfCi[edge.From] = relations
+		}
+
+		graph.Edges = append(graph.Edges, newGraphEdges(edge, relations)...)
+	}
+
+	return
+}
+
+// newGraphEdges returns an edge for each relation of the traversal edge, oriented by the direction of the relation.
+// The direction of the relation type filter is not used, it may match relations of several directions.
+// If the relation no longer exists, an edge of unknown direction is returned.
+func newGraphEdges(edge TraversalEdge, relationsOfFromCi []Relation) (graphEdges []GraphEdge) {
+	for _, relation := range relationsOfFromCi {
+		if relation.OtherCiId(edge.From) != edge.To || relation.RelationTypeName != edge.RelationType {
+			continue
+		}
+		graphEdges = append(graphEdges, newGraphEdge(edge.From, edge.To, edge.RelationType, relation.DirectionFrom(edge.From)))
+	}
+
+	if len(graphEdges) == 0 {
+		graphEdges = append(graphEdges, newGraphEdge(edge.From, edge.To, edge.RelationType, v2.CI_RELATION_DIRECTION_ALL))
+	}
+	return
+}
+
+// newGraphEdge orients the relation: a relation directed from the ci points to it.
+func newGraphEdge(from int, to int, relationType string, direction v2.CiRelationDirection) GraphEdge {
+	if direction == v2.CI_RELATION_DIRECTION_DIRECTED_FROM {
+		return GraphEdge{From: to, To: from, RelationType: relationType, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO}
+	}
+	return GraphEdge{From: from, To: to, RelationType: relationType, Direction: direction}
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g RelationGraph) WriteDOT(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "digraph cis {")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(out, "\t%d [label=%s];\n", node.CiId, dotQuote(node.Label+"\n"+node.CiType))
+	}
+	for _, edge := range g.Edges {
+		dir := "none"
+		switch edge.Direction {
+		case v2.CI_RELATION_DIRECTION_DIRECTED_TO:
+			dir = "forward"
+		case v2.CI_RELATION_DIRECTION_BIDIRECTIONAL:
+			dir = "both"
+		}
+		fmt.Fprintf(out, "\t%d -> %d [label=%s, dir=%s];\n", edge.From, edge.To, dotQuote(edge.RelationType), dir)
+	}
+	fmt.Fprintln(out, "}")
+
+	return out.Flush()
+}
+
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// WriteGraphML writes the graph as GraphML document.
+func (g RelationGraph) WriteGraphML(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, xml.Header+`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(out, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`)
+	fmt.Fprintln(out, `  <key id="ciType" for="node" attr.name="ciType" attr.type="string"/>`)
+	fmt.Fprintln(out, `  <key id="relationType" for="edge" attr.name="relationType" attr.type="string"/>`)
+	fmt.Fprintln(out, `  <key id="direction" for="edge" attr.name="direction" attr.type="string"/>`)
+	fmt.Fprintln(out, `  <graph id="cis" edgedefault="undirected">`)
+	for _, node := range g.Nodes {
+		fmt.Fprintf(out, "    <node id=\"%d\">\n", node.CiId)
+		fmt.Fprintf(out, "      <data key=\"label\">%s</data>\n", xmlEscape(node.Label))
+		fmt.Fprintf(out, "      <data key=\"ciType\">%s</data>\n", xmlEscape(node.CiType))
+		fmt.Fprintln(out, "    </node>")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(out, "    <edge source=\"%d\" target=\"%d\" directed=\"%t\">\n", edge.From, edge.To, edge.Directed())
+		fmt.Fprintf(out, "      <data key=\"relationType\">%s</data>\n", xmlEscape(edge.RelationType))
+		fmt.Fprintf(out, "      <data key=\"direction\">%s</data>\n", xmlEscape(string(edge.Direction)))
+		fmt.Fprintln(out, "    </edge>")
+	}
+	fmt.Fprintln(out, "  </graph>")
+	fmt.Fprintln(out, "</graphml>")
+
+	return out.Flush()
+}
+
+func xmlEscape(s string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
+
+// WriteJSON writes the graph as json object with a list of nodes and a list of edges.
+func (g RelationGraph) WriteJSON(w io.Writer) error {
+	if g.Nodes == nil {
+		g.Nodes = []GraphNode{}
+	}
+	if g.Edges == nil {
+		g.Edges = []GraphEdge{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
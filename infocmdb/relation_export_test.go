@@ -0,0 +1,132 @@
+package infocmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func newTestRelationGraph(t *testing.T) RelationGraph {
+	mockings := []utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiAttributes##{"query":{"params":{"argv1":"1, 2, 3"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"ci_id":"1","attribute_name":"hostname","attribute_type":"input","value":"srv-01"},
+				{"ci_id":"2","attribute_name":"hostname","attribute_type":"input","value":"app \"billing\""}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"1"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[
+				{"id":"50","ci_id_1":"2","ci_id_2":"1","direction":"2","ci_relation_type_id":"31","ci_relation_type_name":"runs_on"},
+				{"id":"51","ci_id_1":"3","ci_id_2":"1","direction":"4","ci_relation_type_id":"32","ci_relation_type_name":"backup"},
+				{"id":"52","ci_id_1":"1","ci_id_2":"2","direction":"2","ci_relation_type_id":"32","ci_relation_type_name":"backup"}]}`,
+		},
+	}
+	for ciId, ciType := range map[string]string{"1": "server", "2": "application", "3": "application"} {
+		mockings = append(mockings, utilTesting.Mocking{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiTypeOfCi##{"query":{"params":{"argv1":"` + ciId + `","argv2":"name"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"` + ciType + `"}]}`,
+		})
+	}
+	cmdb := newTestClient(mockings)
+
+	graph, err := cmdb.NewRelationGraph(TraversalResult{
+		Nodes: []TraversalNode{{CiId: 1}, {CiId: 2, Depth: 1}, {CiId: 3, Depth: 1}},
+		Edges: []TraversalEdge{
+			{From: 1, To: 2, RelationType: "runs_on", Direction: v2.CI_RELATION_DIRECTION_ALL},
+			{From: 1, To: 3, RelationType: "backup", Direction: v2.CI_RELATION_DIRECTION_ALL},
+		},
+	}, "hostname")
+	if err != nil {
+		t.Fatalf("NewRelationGraph() error = %v", err)
+	}
+	return graph
+}
+
+func TestClient_NewRelationGraph(t *testing.T) {
+	graph := newTestRelationGraph(t)
+
+	if len(graph.Nodes) != 3 || graph.Nodes[0].Label != "srv-01" || graph.Nodes[0].CiType != "server" || graph.Nodes[2].Label != "3" {
+		t.Errorf("NewRelationGraph() nodes = %+v", graph.Nodes)
+	}
+	want := GraphEdge{From: 2, To: 1, RelationType: "runs_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO}
+	if len(graph.Edges) != 2 || graph.Edges[0] != want || graph.Edges[1].Directed() {
+		t.Errorf("NewRelationGraph() edges = %+v", graph.Edges)
+	}
+}
+
+func TestRelationGraph_WriteDOT(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestRelationGraph(t).WriteDOT(&out); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+
+	for _, line := range []string{
+		`2 [label="app \"billing\"\napplication"];`,
+		`2 -> 1 [label="runs_on", dir=forward];`,
+		`1 -> 3 [label="backup", dir=none];`,
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("WriteDOT() output does not contain %s:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRelationGraph_WriteGraphML(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestRelationGraph(t).WriteGraphML(&out); err != nil {
+		t.Fatalf("WriteGraphML() error = %v", err)
+	}
+
+	var document struct {
+		Graph struct {
+			Nodes []struct {
+				Id   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source   string `xml:"source,attr"`
+				Target   string `xml:"target,attr"`
+				Directed string `xml:"directed,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("WriteGraphML() wrote invalid xml: %v\n%s", err, out.String())
+	}
+
+	if len(document.Graph.Nodes) != 3 || document.Graph.Nodes[1].Data[0].Value != `app "billing"` {
+		t.Errorf("WriteGraphML() nodes = %+v", document.Graph.Nodes)
+	}
+	if len(document.Graph.Edges) != 2 || document.Graph.Edges[0].Source != "2" || document.Graph.Edges[0].Directed != "true" {
+		t.Errorf("WriteGraphML() edges = %+v", document.Graph.Edges)
+	}
+}
+
+func TestRelationGraph_WriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestRelationGraph(t).WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var got RelationGraph
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid json: %v", err)
+	}
+	if len(got.Nodes) != 3 || len(got.Edges) != 2 || got.Edges[0].Direction != v2.CI_RELATION_DIRECTION_DIRECTED_TO {
+		t.Errorf("WriteJSON() = %s", out.String())
+	}
+
+	out.Reset()
+	if err := (RelationGraph{}).WriteJSON(&out); err != nil || strings.TrimSpace(out.String()) != "{\n  \"nodes\": [],\n  \"edges\": []\n}" {
+		t.Errorf("WriteJSON() of empty graph = %s, %v", out.String(), err)
+	}
+}
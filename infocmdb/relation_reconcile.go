@@ -0,0 +1,299 @@
+package infocmdb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type ReconcileMode string
+
+const (
+	// Create missing relations, existing relations are kept
+	RECONCILE_ADD_ONLY ReconcileMode = "add_only"
+	// Remove relations to cis that are not targets, missing relations are not created
+	RECONCILE_REMOVE_ONLY ReconcileMode = "remove_only"
+	// Create missing and remove other relations
+	RECONCILE_EXACT ReconcileMode = "exact"
+)
+
+// ReconcileSpec describes the desired relations of one type between a source ci and its targets.
+type ReconcileSpec struct {
+	Source       int
+	RelationType string
+	// Direction seen from the source, e.g. CI_RELATION_DIRECTION_DIRECTED_TO for relations pointing to the targets.
+	// Only relations in this direction are reconciled. Defaults to CI_RELATION_DIRECTION_ALL which matches
+	// relations of any direction and creates omnidirectional relations.
+	Direction v2.CiRelationDirection
+	Targets   []int
+	// Defaults to RECONCILE_EXACT
+	Mode ReconcileMode
+}
+
+type ReconcileOptions struct {
+	// Only compute the result, nothing is changed
+	DryRun bool
+}
+
+// RelationChange is a relation that is (or would be) created or removed.
+type RelationChange struct {
+	Source       int
+	Target       int
+	RelationType string
+	// Direction seen from the source
+	Direction v2.CiRelationDirection
+	// Existing relation, empty for created relations
+	Relation Relation
+}
+
+type ReconcileResult struct {
+	DryRun  bool
+	Added   []RelationChange
+	Removed []RelationChange
+	// Relations that already exist as desired
+	Unchanged []RelationChange
+	// Existing relations to targets in another direction, they are only replaced in RECONCILE_EXACT mode
+	Conflicts []RelationChange
+	// Changes that could not be applied, the errors are returned by ReconcileRelations
+	Failed []RelationChange
+}
+
+// ReconcileRelations creates and removes relations so that each source ci is related with its targets.
+//
+// The relations of every source ci are queried once, the changes of all specs are computed before
+// anything is changed. With `DryRun` only the result is returned. Failures of single changes don't stop
+// the reconciliation, they are listed as failed and their errors are returned together.
+func (c *Client) ReconcileRelations(specs []ReconcileSpec, opts ReconcileOptions) (result ReconcileResult, err error) {
+	result.DryRun = opts.DryRun
+
+	seen := map[string]bool{}
+	for i := range specs {
+		if specs[i].Mode == "" {
+			specs[i].Mode = RECONCILE_EXACT
+		}
+		if specs[i].Direction == "" {
+			specs[i].Direction = v2.CI_RELATION_DIRECTION_ALL
+		}
+		if err = validateReconcileSpec(specs[i]); err != nil {
+			log.Error("Error: ", err)
+			return
+		}
+
+		key := strconv.Itoa(specs[i].Source) + "-" + specs[i].RelationType
+		if seen[key] {
+			err = utilError.FunctionError(fmt.Sprintf("%d, %s - duplicate spec", specs[i].Source, specs[i].RelationType))
+			log.Error("Error: ", err)
+			return
+		}
+		seen[key] = true
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	relationsBySource := map[int][]Relation{}
+	for _, spec := range specs {
+		if _, loaded := relationsBySource[spec.Source]; loaded {
+			continue
+		}
+		if relationsBySource[spec.Source], err = c.GetCiRelations(spec.Source); err != nil {
+			return
+		}
+	}
+
+	for _, spec := range specs {
+		var relationTypeId int
+		if relationTypeId, err = c.GetCiRelationTypeIdByRelationTypeName(spec.RelationType); err != nil {
+			return
+		}
+		planRelations(&result, spec, relationTypeId, relationsBySource[spec.Source])
+	}
+
+	if opts.DryRun {
+		return
+	}
+
+	return result, c.applyReconcile(&result)
+}
+
+func validateReconcileSpec(spec ReconcileSpec) error {
+	switch spec.Mode {
+	case RECONCILE_ADD_ONLY, RECONCILE_REMOVE_ONLY, RECONCILE_EXACT:
+	default:
+		return utilError.FunctionError(fmt.Sprintf("%d, %s - invalid mode: %s", spec.Source, spec.RelationType, spec.Mode))
+	}
+
+	if spec.Direction != v2.CI_RELATION_DIRECTION_ALL {
+		if _, err := spec.Direction.GetId(); err != nil {
+			return utilError.FunctionError(fmt.Sprintf("%d, %s - %s", spec.Source, spec.RelationType, err.Error()))
+		}
+	}
+
+	for _, target := range spec.Targets {
+		if target == spec.Source {
+			return utilError.FunctionError(fmt.Sprintf("%d, %s - ci can't be related with itself", spec.Source, spec.RelationType))
+		}
+	}
+
+	return nil
+}
+
+// planRelations adds the changes of the spec to the result.
+func planRelations(result *ReconcileResult, spec ReconcileSpec, relationTypeId int, relations []Relation) {
+	matches := func(relation Relation) bool {
+		return spec.Direction == v2.CI_RELATION_DIRECTION_ALL || relation.DirectionFrom(spec.Source) == spec.Direction
+	}
+	change := func(relation Relation) RelationChange {
+		return RelationChange{
+			Source:       spec.Source,
+			Target:       relation.OtherCiId(spec.Source),
+			RelationType: spec.RelationType,
+			Direction:    relation.DirectionFrom(spec.Source),
+			Relation:     relation,
+		}
+	}
+
+	byTarget := map[int][]Relation{}
+	for _, relation := range relations {
+		if relation.RelationTypeId != relationTypeId {
+			continue
+		}
+		target := relation.OtherCiId(spec.Source)
+		byTarget[target] = append(byTarget[target], relation)
+	}
+
+	targets := map[int]bool{}
+	for _, target := range spec.Targets {
+		targets[target] = true
+	}
+
+	for _, target := range sortedCiIds(targets) {
+		var matching, conflicting []Relation
+		for _, relation := range byTarget[target] {
+			if matches(relation) {
+				matching = append(matching, relation)
+			} else {
+				conflicting = append(conflicting, relation)
+			}
+		}
+
+		if len(matching) > 0 {
+			result.Unchanged = append(result.Unchanged, change(matching[0]))
+			continue
+		}
+		if spec.Mode == RECONCILE_REMOVE_ONLY {
+			continue
+		}
+
+		for _, relation := range conflicting {
+			if spec.Mode == RECONCILE_EXACT {
+				result.Removed = append(result.Removed, change(relation))
+			} else {
+				result.Conflicts = append(result.Conflicts, change(relation))
+			}
+		}
+		if len(conflicting) > 0 && spec.Mode != RECONCILE_EXACT {
+			continue
+		}
+
+		direction := spec.Direction
+		if direction == v2.CI_RELATION_DIRECTION_ALL {
+			direction = v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL
+		}
+		result.Added = append(result.Added, RelationChange{
+			Source:       spec.Source,
+			Target:       target,
+			RelationType: spec.RelationType,
+			Direction:    direction,
+		})
+	}
+
+	if spec.Mode == RECONCILE_ADD_ONLY {
+		return
+	}
+
+	for _, relation := range relations {
+		if relation.RelationTypeId != relationTypeId || targets[relation.OtherCiId(spec.Source)] || !matches(relation) {
+			continue
+		}
+		result.Removed = append(result.Removed, change(relation))
+	}
+}
+
+// applyReconcile removes relations before creating new ones, so a relation in another direction can be replaced.
+//
+// Relations are removed by id, other relations between the same cis (e.g. in the opposite direction) are kept.
+// Relations are created without checking for existing relations, the plan only adds relations that don't exist
+// in the desired direction. A relation is not created if the removal of a relation between the same cis failed.
+func (c *Client) applyReconcile(result *ReconcileResult) error {
+	var errs utilError.Errors
+
+	failedPairs := map[string]bool{}
+	removed := result.Removed[:0]
+	for _, change := range result.Removed {
+		if err := c.DeleteCiRelationById(change.Relation.Id); err != nil {
+			errs = errs.Add(err)
+			result.Failed = append(result.Failed, change)
+			failedPairs[reconcilePairKey(change)] = true
+			continue
+		}
+		removed = append(removed, change)
+	}
+	result.Removed = removed
+
+	added := result.Added[:0]
+	for _, change := range result.Added {
+		if failedPairs[reconcilePairKey(change)] {
+			result.Failed = append(result.Failed, change)
+			continue
+		}
+
+		directionId, err := change.Direction.GetId()
+		if err == nil {
+			err = c.insertCiRelation(change.Source, change.Target, change.RelationType, directionId, nil)
+		}
+		if err != nil {
+			errs = errs.Add(err)
+			result.Failed = append(result.Failed, change)
+			continue
+		}
+		added = append(added, change)
+	}
+	result.Added = added
+
+	if len(errs) > 0 {
+		log.Error("Error: ", errs)
+		return errs
+	}
+	return nil
+}
+
+func reconcilePairKey(change RelationChange) string {
+	return strconv.Itoa(change.Source) + "-" + strconv.Itoa(change.Target) + "-" + change.RelationType
+}
+
+func sortedCiIds(set map[int]bool) []int {
+	ciIds := make([]int, 0, len(set))
+	for ciId := range set {
+		ciIds = append(ciIds, ciId)
+	}
+	sort.Ints(ciIds)
+	return ciIds
+}
+
+// String returns a summary of the result, e.g. for logging dry runs.
+func (r ReconcileResult) String() string {
+	prefix := ""
+	if r.DryRun {
+		prefix = "dry run: "
+	}
+	return prefix + strconv.Itoa(len(r.Added)) + " added, " + strconv.Itoa(len(r.Removed)) + " removed, " +
+		strconv.Itoa(len(r.Unchanged)) + " unchanged, " + strconv.Itoa(len(r.Conflicts)) + " conflicts, " +
+		strconv.Itoa(len(r.Failed)) + " failed"
+}
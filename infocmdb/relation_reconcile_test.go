@@ -0,0 +1,201 @@
+package infocmdb
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_ReconcileRelations(t *testing.T) {
+	emptyResult := `{"success":true,"message":"Query executed successfully","data":[]}`
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"42"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"1","ci_id_1":"42","ci_id_2":"43","direction":"2","ci_relation_type_id":"30"},` +
+				`{"id":"2","ci_id_1":"44","ci_id_2":"42","direction":"2","ci_relation_type_id":"30"},` +
+				`{"id":"3","ci_id_1":"42","ci_id_2":"46","direction":"2","ci_relation_type_id":"30"},` +
+				`{"id":"9","ci_id_1":"46","ci_id_2":"42","direction":"2","ci_relation_type_id":"30"},` +
+				`{"id":"10","ci_id_1":"43","ci_id_2":"42","direction":"2","ci_relation_type_id":"30"},` +
+				`{"id":"4","ci_id_1":"42","ci_id_2":"47","direction":"1","ci_relation_type_id":"30"},` +
+				`{"id":"5","ci_id_1":"42","ci_id_2":"48","direction":"2","ci_relation_type_id":"31"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"50"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"6","ci_id_1":"51","ci_id_2":"50","direction":"3","ci_relation_type_id":"31"},` +
+				`{"id":"7","ci_id_1":"50","ci_id_2":"53","direction":"0","ci_relation_type_id":"31"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"60"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"8","ci_id_1":"61","ci_id_2":"60","direction":"2","ci_relation_type_id":"30"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCiRelationById##{"query":{"params":{"argv1":"2"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCiRelationById##{"query":{"params":{"argv1":"3"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelation##{"query":{"params":{"argv1":"42","argv2":"44","argv3":"30","argv4":"2"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelation##{"query":{"params":{"argv1":"42","argv2":"45","argv3":"30","argv4":"2"}}}`,
+			ReturnString:  emptyResult,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelation##{"query":{"params":{"argv1":"50","argv2":"52","argv3":"31","argv4":"4"}}}`,
+			ReturnString:  emptyResult,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "runs_on", 31)
+
+	specs := func() []ReconcileSpec {
+		return []ReconcileSpec{
+			{Source: 42, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, Targets: []int{45, 43, 44}},
+			{Source: 50, RelationType: "runs_on", Targets: []int{51, 52}, Mode: RECONCILE_ADD_ONLY},
+			{Source: 60, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, Targets: []int{61}, Mode: RECONCILE_ADD_ONLY},
+		}
+	}
+
+	added := []RelationChange{
+		{Source: 42, Target: 44, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO},
+		{Source: 42, Target: 45, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO},
+		{Source: 50, Target: 52, RelationType: "runs_on", Direction: v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL},
+	}
+	removed := []RelationChange{
+		{
+			Source: 42, Target: 44, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_FROM,
+			Relation: Relation{Id: 2, CiId1: 44, CiId2: 42, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, RelationTypeId: 30},
+		},
+		{
+			Source: 42, Target: 46, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO,
+			Relation: Relation{Id: 3, CiId1: 42, CiId2: 46, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, RelationTypeId: 30},
+		},
+	}
+	unchanged := []RelationChange{
+		{
+			Source: 42, Target: 43, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO,
+			Relation: Relation{Id: 1, CiId1: 42, CiId2: 43, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, RelationTypeId: 30},
+		},
+		{
+			Source: 50, Target: 51, RelationType: "runs_on", Direction: v2.CI_RELATION_DIRECTION_BIDIRECTIONAL,
+			Relation: Relation{Id: 6, CiId1: 51, CiId2: 50, Direction: v2.CI_RELATION_DIRECTION_BIDIRECTIONAL, RelationTypeId: 31},
+		},
+	}
+	conflicts := []RelationChange{
+		{
+			Source: 60, Target: 61, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_FROM,
+			Relation: Relation{Id: 8, CiId1: 61, CiId2: 60, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, RelationTypeId: 30},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		specs      []ReconcileSpec
+		dryRun     bool
+		want       ReconcileResult
+		wantString string
+		wantErr    bool
+	}{
+		{
+			name:       "dry run",
+			specs:      specs(),
+			dryRun:     true,
+			want:       ReconcileResult{DryRun: true, Added: added, Removed: removed, Unchanged: unchanged, Conflicts: conflicts},
+			wantString: "dry run: 3 added, 2 removed, 2 unchanged, 1 conflicts, 0 failed",
+		},
+		{
+			name:       "apply",
+			specs:      specs(),
+			want:       ReconcileResult{Added: added, Removed: removed, Unchanged: unchanged, Conflicts: conflicts},
+			wantString: "3 added, 2 removed, 2 unchanged, 1 conflicts, 0 failed",
+		},
+		{
+			name:    "invalid mode",
+			specs:   []ReconcileSpec{{Source: 42, RelationType: "depends_on", Mode: "sync"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate spec",
+			specs:   []ReconcileSpec{{Source: 42, RelationType: "depends_on"}, {Source: 42, RelationType: "depends_on"}},
+			wantErr: true,
+		},
+		{
+			name:    "relation with itself",
+			specs:   []ReconcileSpec{{Source: 42, RelationType: "depends_on", Targets: []int{42}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.ReconcileRelations(tt.specs, ReconcileOptions{DryRun: tt.dryRun})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReconcileRelations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReconcileRelations() got = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("String() got = %v, want %v", got.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestClient_ReconcileRelations_failedRemoval(t *testing.T) {
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"70"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"11","ci_id_1":"71","ci_id_2":"70","direction":"2","ci_relation_type_id":"30"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_deleteCiRelationById##{"query":{"params":{"argv1":"11"}}}`,
+			StatusCode:    http.StatusBadRequest,
+		},
+	})
+
+	got, err := cmdb.ReconcileRelations([]ReconcileSpec{
+		{Source: 70, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, Targets: []int{71}},
+	}, ReconcileOptions{})
+	if err == nil {
+		t.Fatalf("ReconcileRelations() error = nil, want error of the failed removal")
+	}
+
+	want := ReconcileResult{
+		Added:   []RelationChange{},
+		Removed: []RelationChange{},
+		Failed: []RelationChange{
+			{
+				Source: 70, Target: 71, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_FROM,
+				Relation: Relation{Id: 11, CiId1: 71, CiId2: 70, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO, RelationTypeId: 30},
+			},
+			{Source: 70, Target: 71, RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReconcileRelations() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestRelation_DirectionFrom(t *testing.T) {
+	relation := Relation{CiId1: 1, CiId2: 2, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO}
+	if got := relation.DirectionFrom(1); got != v2.CI_RELATION_DIRECTION_DIRECTED_TO {
+		t.Errorf("DirectionFrom(1) got = %v, want %v", got, v2.CI_RELATION_DIRECTION_DIRECTED_TO)
+	}
+	if got := relation.DirectionFrom(2); got != v2.CI_RELATION_DIRECTION_DIRECTED_FROM {
+		t.Errorf("DirectionFrom(2) got = %v, want %v", got, v2.CI_RELATION_DIRECTION_DIRECTED_FROM)
+	}
+}
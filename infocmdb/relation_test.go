@@ -0,0 +1,157 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func newRelationTestClient(mocks []utilTesting.Mocking) *Client {
+	cmdb := newTestClient(mocks)
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "depends_on", 30)
+	return cmdb
+}
+
+func TestClient_GetCiRelations(t *testing.T) {
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"42"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"7","ci_id_1":"42","ci_id_2":"43","direction":"2","ci_relation_type_id":"30","ci_relation_type_name":"depends_on",` +
+				`"weighting":"5","color":"FF0000","note":"primary","user_id":"1","valid_from":"2019-11-27 15:53:32"},` +
+				`{"id":"8","ci_id_1":"44","ci_id_2":"42","direction":"4","ci_relation_type_id":"31","ci_relation_type_name":"runs_on",` +
+				`"weighting":null,"color":null,"note":null,"user_id":"0","valid_from":null}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelations##{"query":{"params":{"argv1":"99"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	tests := []struct {
+		name    string
+		ciId    int
+		want    []Relation
+		wantErr bool
+	}{
+		{
+			name: "relations",
+			ciId: 42,
+			want: []Relation{
+				{
+					Id: 7, CiId1: 42, CiId2: 43, Direction: v2.CI_RELATION_DIRECTION_DIRECTED_TO,
+					RelationTypeId: 30, RelationTypeName: "depends_on", Weighting: 5, Color: "FF0000", Note: "primary",
+					UserId: 1, ValidFrom: time.Date(2019, 11, 27, 15, 53, 32, 0, v2.FlexTimeLocation),
+				},
+				{
+					Id: 8, CiId1: 44, CiId2: 42, Direction: v2.CI_RELATION_DIRECTION_OMNIDIRECTIONAL,
+					RelationTypeId: 31, RelationTypeName: "runs_on",
+				},
+			},
+		},
+		{name: "no relations", ciId: 99, want: []Relation{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.GetCiRelations(tt.ciId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCiRelations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCiRelations() got = %+v, want %+v", got, tt.want)
+			}
+			for _, relation := range got {
+				if relation.OtherCiId(tt.ciId) == tt.ciId {
+					t.Errorf("OtherCiId(%d) returned the ci itself for relation %d", tt.ciId, relation.Id)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetListOfRelationsByName(t *testing.T) {
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationsByName##{"query":{"params":{"argv1":"depends_on"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[` +
+				`{"id":"7","ci_id_1":"42","ci_id_2":"43","direction":"3","weighting":"2","note":"backup"}]}`,
+		},
+	})
+
+	want := []Relation{
+		{
+			Id: 7, CiId1: 42, CiId2: 43, Direction: v2.CI_RELATION_DIRECTION_BIDIRECTIONAL,
+			RelationTypeName: "depends_on", Weighting: 2, Note: "backup",
+		},
+	}
+
+	got, err := cmdb.GetListOfRelationsByName("depends_on")
+	if err != nil {
+		t.Fatalf("GetListOfRelationsByName() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetListOfRelationsByName() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_CreateCiRelationWithAttributes(t *testing.T) {
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"42","argv2":"43","argv3":"30"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"0"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_createCiRelationWithAttributes##` +
+				`{"query":{"params":{"argv1":"42","argv2":"43","argv3":"30","argv4":"2","argv5":"5","argv6":"FF0000","argv7":"primary"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationCount##{"query":{"params":{"argv1":"42","argv2":"44","argv3":"30"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"c":"1"}]}`,
+		},
+	})
+
+	tests := []struct {
+		name    string
+		ciId2   int
+		wantErr bool
+	}{
+		{"new relation", 43, false},
+		{"existing relation", 44, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cmdb.CreateCiRelationWithAttributes(42, tt.ciId2, "depends_on", v2.CI_RELATION_DIRECTION_DIRECTED_TO,
+				RelationAttributes{Weighting: 5, Color: "FF0000", Note: "primary"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateCiRelationWithAttributes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateCiRelation(t *testing.T) {
+	cmdb := newRelationTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_updateCiRelation##{"query":{"params":{"argv1":"7","argv2":"3","argv3":"00FF00","argv4":"secondary"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_updateCiRelation##{"query":{"params":{"argv1":"8","argv2":"0","argv3":"","argv4":""}}}`,
+			ReturnString:  `{"success":false,"message":"Query failed","data":[]}`,
+		},
+	})
+
+	err := cmdb.UpdateCiRelation(7, RelationAttributes{Weighting: 3, Color: "00FF00", Note: "secondary"})
+	if err != nil {
+		t.Errorf("UpdateCiRelation() error = %v", err)
+	}
+
+	if err = cmdb.UpdateCiRelation(8, RelationAttributes{}); err == nil {
+		t.Error("UpdateCiRelation() error = nil, want error of the failed query")
+	}
+}
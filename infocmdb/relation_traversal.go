@@ -0,0 +1,249 @@
+package infocmdb
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+)
+
+type TraversalOrder string
+
+const (
+	TRAVERSAL_BREADTH_FIRST TraversalOrder = "breadth_first"
+	TRAVERSAL_DEPTH_FIRST   TraversalOrder = "depth_first"
+)
+
+// TraversalRelation is a relation type that is followed in the given direction.
+type TraversalRelation struct {
+	RelationType string
+	// Defaults to CI_RELATION_DIRECTION_ALL
+	Direction v2.CiRelationDirection
+}
+
+type TraversalOptions struct {
+	// Defaults to TRAVERSAL_BREADTH_FIRST
+	Order     TraversalOrder
+	Relations []TraversalRelation
+	// Maximum number of relations between the start ci and a reached ci, 0 for no limit
+	MaxDepth int
+	// Visit is called for every reached ci including the start ci, in traversal order.
+	// The relations of the ci are not followed if it returns false, the traversal is aborted if it returns an error.
+	Visit func(node TraversalNode) (follow bool, err error)
+}
+
+// TraversalEdge is a relation found during the traversal, From is the ci whose relations were queried.
+type TraversalEdge struct {
+	From         int
+	To           int
+	RelationType string
+	Direction    v2.CiRelationDirection
+}
+
+type TraversalNode struct {
+	CiId  int
+	Depth int
+	// Ci ids from the start ci to this ci, both included
+	Path []int
+	// Relation the ci was reached by, empty for the start ci
+	Via TraversalEdge
+}
+
+type TraversalResult struct {
+	// Reached cis in traversal order, the first node is the start ci
+	Nodes []TraversalNode
+	// All relations between reached cis, each relation is contained once
+	Edges []TraversalEdge
+	// Relations that were not followed because their ci had already been reached, each closes a cycle
+	Cycles []TraversalEdge
+}
+
+var errTraversalNoRelations = errors.New("no relations to traverse")
+
+// TraverseCiRelations follows the given relations starting at a ci, each ci is visited once.
+//
+// E.g. all applications transitively depending on a server:
+//
+//	result, err := cmdb.TraverseCiRelations(serverId, infocmdb.TraversalOptions{
+//		Relations: []infocmdb.TraversalRelation{{RelationType: "depends_on", Direction: v2.CI_RELATION_DIRECTION_DIRECTED_FROM}},
+//	})
+func (c *Client) TraverseCiRelations(startCiId int, opts TraversalOptions) (result TraversalResult, err error) {
+	if len(opts.Relations) == 0 {
+		return result, errTraversalNoRelations
+	}
+
+	t := &traversal{
+		client:  c,
+		opts:    opts,
+		visited: map[int]bool{},
+		edges:   map[string]bool{},
+	}
+
+	start := TraversalNode{CiId: startCiId, Path: []int{startCiId}}
+	if opts.Order == TRAVERSAL_DEPTH_FIRST {
+		err = t.depthFirst(start)
+	} else {
+		err = t.breadthFirst(start)
+	}
+
+	followed := map[string]bool{}
+	for _, node := range t.result.Nodes {
+		if node.Depth > 0 {
+			followed[edgeKey(node.Via)] = true
+		}
+	}
+	for _, edge := range t.result.Edges {
+		if !followed[edgeKey(edge)] {
+			t.result.Cycles = append(t.result.Cycles, edge)
+		}
+	}
+
+	return t.result, err
+}
+
+type traversal struct {
+	client  *Client
+	opts    TraversalOptions
+	visited map[int]bool
+	edges   map[string]bool
+	result  TraversalResult
+}
+
+// visit records the node and returns whether its relations are followed.
+func (t *traversal) visit(node TraversalNode) (follow bool, err error) {
+	t.visited[node.CiId] = true
+	t.result.Nodes = append(t.result.Nodes, node)
+
+	follow = t.opts.MaxDepth <= 0 || node.Depth < t.opts.MaxDepth
+	if t.opts.Visit != nil {
+		var visitorFollows bool
+		if visitorFollows, err = t.opts.Visit(node); err != nil {
+			return false, err
+		}
+		follow = follow && visitorFollows
+	}
+	return
+}
+
+// neighbours returns the new relations of the ci to cis that have not been visited yet.
+func (t *traversal) neighbours(node TraversalNode) (next []TraversalNode, err error) {
+	for _, relation := range t.opts.Relations {
+		direction := relation.Direction
+		if direction == "" {
+			direction = v2.CI_RELATION_DIRECTION_ALL
+		}
+
+		ciIds, err := t.client.GetListOfCiIdsByCiRelation(node.CiId, relation.RelationType, direction)
+		if err != nil {
+			return nil, err
+		}
+		sort.Ints(ciIds)
+
+		for _, ciId := range ciIds {
+			edge := TraversalEdge{From: node.CiId, To: ciId, RelationType: relation.RelationType, Direction: direction}
+			if !t.addEdge(edge) {
+				continue
+			}
+
+			if t.visited[ciId] {
+				continue
+			}
+
+			path := make([]int, len(node.Path), len(node.Path)+1)
+			copy(path, node.Path)
+			next = append(next, TraversalNode{CiId: ciId, Depth: node.Depth + 1, Path: append(path, ciId), Via: edge})
+		}
+	}
+	return
+}
+
+// addEdge records the relation, false if it was already found from the other ci.
+func (t *traversal) addEdge(edge TraversalEdge) bool {
+	key := edgeKey(edge)
+	if t.edges[key] {
+		return false
+	}
+
+	t.edges[key] = true
+	t.result.Edges = append(t.result.Edges, edge)
+	return true
+}
+
+// edgeKey identifies a relation independent of the ci it was found from.
+func edgeKey(edge TraversalEdge) string {
+	first, second := edge.From, edge.To
+	if first > second {
+		first, second = second, first
+	}
+	return strconv.Itoa(first) + "-" + strconv.Itoa(second) + "-" + edge.RelationType
+}
+
+func (t *traversal) breadthFirst(start TraversalNode) error {
+	queue := []TraversalNode{start}
+	t.visited[start.CiId] = true
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		follow, err := t.visit(node)
+		if err != nil {
+			return err
+		}
+		if !follow {
+			continue
+		}
+
+		next, err := t.neighbours(node)
+		if err != nil {
+			return err
+		}
+		for _, neighbour := range next {
+			// mark when queued so that a ci reached by several relations is only visited once
+			t.visited[neighbour.CiId] = true
+			queue = append(queue, neighbour)
+		}
+	}
+	return nil
+}
+
+func (t *traversal) depthFirst(node TraversalNode) error {
+	follow, err := t.visit(node)
+	if err != nil || !follow {
+		return err
+	}
+
+	next, err := t.neighbours(node)
+	if err != nil {
+		return err
+	}
+	for _, neighbour := range next {
+		// the ci may have been reached through an earlier neighbour meanwhile
+		if t.visited[neighbour.CiId] {
+			continue
+		}
+		if err = t.depthFirst(neighbour); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CiIds returns the ids of the reached cis in traversal order.
+func (r TraversalResult) CiIds() (ciIds []int) {
+	for _, node := range r.Nodes {
+		ciIds = append(ciIds, node.CiId)
+	}
+	return
+}
+
+// PathTo returns the ci ids from the start ci to the given ci.
+func (r TraversalResult) PathTo(ciId int) ([]int, bool) {
+	for _, node := range r.Nodes {
+		if node.CiId == ciId {
+			return node.Path, true
+		}
+	}
+	return nil, false
+}
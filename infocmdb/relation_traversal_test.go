@@ -0,0 +1,103 @@
+package infocmdb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+// newTraversalTestClient mocks the relations 1-2, 1-3, 2-4 and 4-1 of type depends_on
+func newTraversalTestClient() *Client {
+	var mockings []utilTesting.Mocking
+	for ciId, related := range map[string]string{
+		"1": `[{"ci_id":"3"},{"ci_id":"2"},{"ci_id":"4"}]`,
+		"2": `[{"ci_id":"1"},{"ci_id":"4"}]`,
+		"3": `[{"ci_id":"1"}]`,
+		"4": `[{"ci_id":"2"},{"ci_id":"1"}]`,
+	} {
+		mockings = append(mockings, utilTesting.Mocking{
+			RequestString: `PUT##/apiV2/query/execute/int_getListOfCiIdsByCiRelation_directionList##{"query":{"params":{"argv1":"` + ciId + `","argv2":"30","argv3":"0,1,2,3,4"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":` + related + `}`,
+		})
+	}
+
+	cmdb := newTestClient(mockings)
+	cmdb.metadataCache().Set(METADATA_RELATION_TYPE, "depends_on", 30)
+	return cmdb
+}
+
+func TestClient_TraverseCiRelations(t *testing.T) {
+	cmdb := newTraversalTestClient()
+	relations := []TraversalRelation{{RelationType: "depends_on"}}
+
+	tests := []struct {
+		name       string
+		opts       TraversalOptions
+		wantCiIds  []int
+		wantPath   []int
+		wantEdges  int
+		wantCycles int
+	}{
+		{"breadth first", TraversalOptions{Relations: relations}, []int{1, 2, 3, 4}, []int{1, 4}, 4, 1},
+		{"depth first", TraversalOptions{Order: TRAVERSAL_DEPTH_FIRST, Relations: relations}, []int{1, 2, 4, 3}, []int{1, 2, 4}, 4, 1},
+		{"max depth", TraversalOptions{Relations: relations, MaxDepth: 1}, []int{1, 2, 3, 4}, []int{1, 4}, 3, 0},
+		{
+			"visitor stops at ci",
+			TraversalOptions{
+				Order:     TRAVERSAL_DEPTH_FIRST,
+				Relations: relations,
+				Visit:     func(node TraversalNode) (bool, error) { return node.CiId != 2, nil },
+			},
+			[]int{1, 2, 3, 4},
+			[]int{1, 4},
+			4,
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmdb.TraverseCiRelations(1, tt.opts)
+			if err != nil {
+				t.Fatalf("TraverseCiRelations() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.CiIds(), tt.wantCiIds) {
+				t.Errorf("CiIds() = %v, want %v", got.CiIds(), tt.wantCiIds)
+			}
+			if path, _ := got.PathTo(4); !reflect.DeepEqual(path, tt.wantPath) {
+				t.Errorf("PathTo(4) = %v, want %v", path, tt.wantPath)
+			}
+			if len(got.Edges) != tt.wantEdges || len(got.Cycles) != tt.wantCycles {
+				t.Errorf("TraverseCiRelations() edges = %v, cycles = %v", got.Edges, got.Cycles)
+			}
+		})
+	}
+}
+
+func TestClient_TraverseCiRelations_visitorError(t *testing.T) {
+	cmdb := newTraversalTestClient()
+	errStop := errors.New("stop")
+
+	visited := 0
+	_, err := cmdb.TraverseCiRelations(1, TraversalOptions{
+		Relations: []TraversalRelation{{RelationType: "depends_on"}},
+		Visit: func(node TraversalNode) (bool, error) {
+			visited++
+			if node.CiId == 2 {
+				return false, errStop
+			}
+			return true, nil
+		},
+	})
+	if err != errStop {
+		t.Errorf("TraverseCiRelations() error = %v, want %v", err, errStop)
+	}
+	if visited != 2 {
+		t.Errorf("TraverseCiRelations() visited %d cis after error, want 2", visited)
+	}
+
+	if _, err = cmdb.TraverseCiRelations(1, TraversalOptions{}); err == nil {
+		t.Error("TraverseCiRelations() without relations error = nil")
+	}
+}
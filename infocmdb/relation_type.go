@@ -0,0 +1,213 @@
+package infocmdb
+
+import (
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+type CiRelationType struct {
+	Id   v2.FlexInt `json:"id"`
+	Name string     `json:"name"`
+	// Description of the direction from ci 1 to ci 2
+	Description string `json:"description"`
+	// Description of the direction from ci 2 to ci 1
+	DescriptionOptional string      `json:"description_optional"`
+	Note                string      `json:"note"`
+	Color               string      `json:"color"`
+	Visualize           v2.FlexBool `json:"visualize"`
+	IsActive            v2.FlexBool `json:"is_active"`
+	UserId              v2.FlexInt  `json:"user_id"`
+	ValidFrom           v2.FlexTime `json:"valid_from"`
+	// Names of the ci types that may be related with this relation type
+	AllowedCiTypes []string `json:"-"`
+}
+
+type CiRelationTypeParams struct {
+	Name                string
+	Description         string
+	DescriptionOptional string
+	Note                string
+	// Hex color of the relation in the visualization, e.g. "FF0000"
+	Color     string
+	Visualize int
+	IsActive  int
+	UserId    int
+	// Names of the ci types that may be related with this relation type
+	AllowedCiTypes []string
+}
+
+func (c *Client) NewCiRelationTypeParams() (params *CiRelationTypeParams) {
+	params = &CiRelationTypeParams{
+		Name:                "",
+		Description:         "",
+		DescriptionOptional: "",
+		Note:                "",
+		Color:               "",
+		Visualize:           1,
+		IsActive:            1,
+		UserId:              0,
+	}
+	return
+}
+
+type respCreateCiRelationType struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    []responseId `json:"data"`
+}
+
+// sqlValueList quotes the values as sql string literals, quotes and backslashes in the values are escaped.
+func sqlValueList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + sqlStringEscaper.Replace(value) + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+var sqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// CreateCiRelationType creates the relation type and allows it for the given ci types.
+//
+// If a relation type with the name already exists, its id is returned and it is not changed.
+func (c *Client) CreateCiRelationType(relationTypeParams *CiRelationTypeParams) (relationTypeId int, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	existingRelationTypeId, err := c.GetCiRelationTypeIdByRelationTypeName(relationTypeParams.Name)
+	if err != nil && !strings.Contains(err.Error(), v2.ErrNoResult.Error()) {
+		return 0, err
+	}
+	if existingRelationTypeId != 0 {
+		return existingRelationTypeId, nil
+	}
+
+	ciTypeIds := make([]int, len(relationTypeParams.AllowedCiTypes))
+	for i, ciType := range relationTypeParams.AllowedCiTypes {
+		if ciTypeIds[i], err = c.GetCiTypeIdByCiTypeName(ciType); err != nil {
+			return
+		}
+	}
+
+	columns := []string{
+		"name",
+		"description",
+		"description_optional",
+		"note",
+		"color",
+		"visualize",
+		"is_active",
+		"user_id",
+	}
+
+	values := []string{
+		relationTypeParams.Name,
+		relationTypeParams.Description,
+		relationTypeParams.DescriptionOptional,
+		relationTypeParams.Note,
+		relationTypeParams.Color,
+		strconv.Itoa(relationTypeParams.Visualize),
+		strconv.Itoa(relationTypeParams.IsActive),
+		strconv.Itoa(relationTypeParams.UserId),
+	}
+
+	params := map[string]string{
+		"argv1": "`" + strings.Join(columns, "`, `") + "`",
+		"argv2": sqlValueList(values),
+	}
+
+	response := respCreateCiRelationType{}
+	err = c.v2.Query("int_createCiRelationType", &response, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	switch len(response.Data) {
+	case 0:
+		err = utilError.FunctionError(relationTypeParams.Name + " - " + v2.ErrNoResult.Error())
+		return
+	case 1:
+		relationTypeId = response.Data[0].Id.Int()
+		c.InvalidateMetadata(METADATA_RELATION_TYPE)
+	default:
+		err = utilError.FunctionError(relationTypeParams.Name + " - " + v2.ErrTooManyResults.Error())
+		return
+	}
+
+	for _, ciTypeId := range ciTypeIds {
+		if err = c.addCiTypeRelationType(ciTypeId, relationTypeId); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+type respAddCiTypeRelationType struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func (c *Client) addCiTypeRelationType(ciTypeId int, relationTypeId int) (err error) {
+	params := map[string]string{
+		"argv1": strconv.Itoa(ciTypeId),
+		"argv2": strconv.Itoa(relationTypeId),
+	}
+
+	response := respAddCiTypeRelationType{}
+	err = c.v2.Query("int_addCiTypeRelationType", &response, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+	}
+
+	return
+}
+
+type getCiRelationType struct {
+	Name string `argv:"1"`
+}
+
+func (getCiRelationType) QueryName() string { return "int_getCiRelationType" }
+
+type getCiTypesOfCiRelationType struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+// GetCiRelationType returns the definition of the relation type including the ci types it is allowed for.
+func (c *Client) GetCiRelationType(name string) (relationType CiRelationType, err error) {
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	if err = c.queryOne(getCiRelationType{Name: name}, &relationType, name); err != nil {
+		return
+	}
+
+	ciTypes := getCiTypesOfCiRelationType{}
+	err = c.v2.Query("int_getCiTypesOfCiRelationType", &ciTypes, map[string]string{
+		"argv1": strconv.Itoa(relationType.Id.Int()),
+	})
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	relationType.AllowedCiTypes = []string{}
+	for _, ciType := range ciTypes.Data {
+		relationType.AllowedCiTypes = append(relationType.AllowedCiTypes, ciType.Name)
+	}
+
+	return
+}
@@ -0,0 +1,105 @@
+package infocmdb
+
+import (
+	"reflect"
+	"testing"
+
+	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
+)
+
+func TestClient_CreateCiRelationType(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationTypeIdByRelationTypeName##{"query":{"params":{"argv1":"runs_on"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationTypeIdByRelationTypeName##{"query":{"params":{"argv1":"depends_on"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"id":"30"}]}`,
+		},
+		{
+			RequestString: "PUT##/apiV2/query/execute/int_createCiRelationType##" +
+				`{"query":{"params":{"argv1":"` + "`name`, `description`, `description_optional`, `note`, `color`, `visualize`, `is_active`, `user_id`" +
+				`","argv2":"'runs_on', 'runs on', 'hosts', '', 'FF0000', '1', '1', '0'"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[{"id":"31"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiTypeRelationType##{"query":{"params":{"argv1":"12","argv2":"31"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_addCiTypeRelationType##{"query":{"params":{"argv1":"13","argv2":"31"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "application", 12)
+	cmdb.metadataCache().Set(METADATA_CI_TYPE, "server", 13)
+
+	tests := []struct {
+		name         string
+		relationType string
+		want         int
+	}{
+		{"new", "runs_on", 31},
+		{"existing", "depends_on", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := cmdb.NewCiRelationTypeParams()
+			params.Name = tt.relationType
+			params.Description = "runs on"
+			params.DescriptionOptional = "hosts"
+			params.Color = "FF0000"
+			params.AllowedCiTypes = []string{"application", "server"}
+
+			got, err := cmdb.CreateCiRelationType(params)
+			if err != nil {
+				t.Fatalf("CreateCiRelationType() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CreateCiRelationType() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_GetCiRelationType(t *testing.T) {
+	cmdb := newTestClient([]utilTesting.Mocking{
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationType##{"query":{"params":{"argv1":"runs_on"}}}`,
+			ReturnString: `{"success":true,"message":"Query executed successfully","data":[{"id":"31","name":"runs_on","description":"runs on",` +
+				`"description_optional":"hosts","note":"","color":"FF0000","visualize":"1","is_active":"1","user_id":"0","valid_from":"2020-01-13 15:14:05"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiTypesOfCiRelationType##{"query":{"params":{"argv1":"31"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"application"},{"name":"server"}]}`,
+		},
+		{
+			RequestString: `PUT##/apiV2/query/execute/int_getCiRelationType##{"query":{"params":{"argv1":"not_existing"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+		},
+	})
+
+	got, err := cmdb.GetCiRelationType("runs_on")
+	if err != nil {
+		t.Fatalf("GetCiRelationType() error = %v", err)
+	}
+	if got.Id != 31 || got.DescriptionOptional != "hosts" || !got.Visualize.Bool() || got.ValidFrom.IsZero() {
+		t.Errorf("GetCiRelationType() got = %+v", got)
+	}
+	if !reflect.DeepEqual(got.AllowedCiTypes, []string{"application", "server"}) {
+		t.Errorf("GetCiRelationType() allowed ci types = %v", got.AllowedCiTypes)
+	}
+
+	if _, err = cmdb.GetCiRelationType("not_existing"); err == nil {
+		t.Error("GetCiRelationType() of not existing relation type error = nil")
+	}
+}
+
+func Test_sqlValueList(t *testing.T) {
+	got := sqlValueList([]string{"runs on", "Bob's server", `C:\`, ""})
+	want := `'runs on', 'Bob''s server', 'C:\\', ''`
+	if got != want {
+		t.Errorf("sqlValueList() = %v, want %v", got, want)
+	}
+}
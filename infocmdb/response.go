@@ -1,5 +1,7 @@
 package infocmdb
 
+import v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+
 type responseId struct {
-	Id int `json:"id,string"`
+	Id v2.FlexInt `json:"id"`
 }
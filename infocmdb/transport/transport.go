@@ -0,0 +1,123 @@
+package transport
+
+// Creation of the http clients used by the v1 and v2 api.
+//
+// Supports custom CA bundles, client certificates (mTLS), disabling certificate verification (development only),
+// http proxies, request timeouts and injecting a custom `http.RoundTripper`.
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Options of the http transport.
+type Options struct {
+	// PEM encoded CA certificates trusted in addition to the system pool
+	CaFile string
+	// PEM encoded client certificate and key for mutual TLS
+	CertFile string
+	KeyFile  string
+	// Disables verification of server certificates, never use this in production
+	InsecureSkipVerify bool
+	// Proxy for all requests, if empty the HTTP_PROXY/HTTPS_PROXY/NO_PROXY env variables are used
+	ProxyUrl string
+	// Timeout of a single request including reading the response, 0 means no timeout
+	Timeout time.Duration
+	// Custom round tripper, all other transport options are ignored if set
+	RoundTripper http.RoundTripper
+}
+
+// IsDefault reports whether no option is set and the default http transport can be used.
+func (options Options) IsDefault() bool {
+	return options.CaFile == "" &&
+		options.CertFile == "" &&
+		options.KeyFile == "" &&
+		!options.InsecureSkipVerify &&
+		options.ProxyUrl == "" &&
+		options.Timeout == 0 &&
+		options.RoundTripper == nil
+}
+
+// NewHTTPClient returns a http client configured with the given options.
+func NewHTTPClient(options Options) (httpClient *http.Client, err error) {
+	httpClient = &http.Client{
+		Timeout: options.Timeout,
+	}
+
+	if options.RoundTripper != nil {
+		httpClient.Transport = options.RoundTripper
+		return
+	}
+
+	httpClient.Transport, err = NewTransport(options)
+	return
+}
+
+// NewTransport returns a http transport configured with the given TLS and proxy options.
+func NewTransport(options Options) (transport *http.Transport, err error) {
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+	if options.InsecureSkipVerify {
+		log.Warn("TLS certificate verification is disabled")
+	}
+
+	if options.CaFile != "" {
+		tlsConfig.RootCAs, err = loadCertPool(options.CaFile)
+		if err != nil {
+			return
+		}
+	}
+
+	if options.CertFile != "" || options.KeyFile != "" {
+		if options.CertFile == "" || options.KeyFile == "" {
+			return nil, errors.New("client certificate and key file must be provided together")
+		}
+
+		var certificate tls.Certificate
+		certificate, err = tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, errors.New("failed to load client certificate: " + err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if options.ProxyUrl != "" {
+		var proxyUrl *url.URL
+		proxyUrl, err = url.Parse(options.ProxyUrl)
+		if err != nil {
+			return nil, errors.New("invalid proxy url: " + err.Error())
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	return
+}
+
+func loadCertPool(caFile string) (pool *x509.CertPool, err error) {
+	pool, err = x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	caBytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.New("failed to read CA file: " + err.Error())
+	}
+
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, errors.New("no PEM encoded certificates found in CA file " + caFile)
+	}
+
+	return
+}
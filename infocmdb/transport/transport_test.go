@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "infocmdb-transport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err = ioutil.WriteFile(caFile, caPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidCaFile := filepath.Join(dir, "invalid.pem")
+	if err = ioutil.WriteFile(invalidCaFile, []byte("no certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		options        Options
+		wantErr        bool
+		wantRequestErr bool
+	}{
+		{
+			name:           "unknown certificate authority",
+			options:        Options{},
+			wantRequestErr: true,
+		},
+		{
+			name:    "custom certificate authority",
+			options: Options{CaFile: caFile, Timeout: 5 * time.Second},
+		},
+		{
+			name:    "insecure skip verify",
+			options: Options{InsecureSkipVerify: true},
+		},
+		{
+			name:    "invalid CA file",
+			options: Options{CaFile: invalidCaFile},
+			wantErr: true,
+		},
+		{
+			name:    "missing client key",
+			options: Options{CertFile: caFile},
+			wantErr: true,
+		},
+		{
+			name: "custom round tripper",
+			options: Options{
+				CaFile: invalidCaFile,
+				RoundTripper: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody, Request: req}, nil
+				}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := NewHTTPClient(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHTTPClient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if httpClient.Timeout != tt.options.Timeout {
+				t.Errorf("NewHTTPClient() timeout = %v, want %v", httpClient.Timeout, tt.options.Timeout)
+			}
+
+			resp, err := httpClient.Get(server.URL)
+			if (err != nil) != tt.wantRequestErr {
+				t.Errorf("Get() error = %v, wantRequestErr %v", err, tt.wantRequestErr)
+				return
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		})
+	}
+}
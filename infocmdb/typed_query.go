@@ -0,0 +1,312 @@
+package infocmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+	utilError "github.com/infonova/infocmdb-sdk-go/util/error"
+)
+
+// TypedQuery declares a webservice query as Go type.
+//
+// Exported fields tagged with `argv:"N"` are passed as parameter argvN:
+//
+//	type getCiIdsOfCiType struct {
+//		CiTypeId int `argv:"1"`
+//	}
+//
+//	func (getCiIdsOfCiType) QueryName() string { return "int_getListOfCiIdsOfCiType" }
+//
+// Strings are passed as is, numbers are formatted in decimal, booleans as "1" or "0", times as "2006-01-02 15:04:05"
+// in `v2.FlexTimeLocation` and slices are joined with commas (e.g. lists of ci ids). Nil pointers are passed as "".
+type TypedQuery interface {
+	QueryName() string
+}
+
+type typedQueryResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// ExecuteQuery executes the query and decodes the result rows into rows, which must be a pointer to a slice of structs.
+//
+// Row fields are matched by their json tag (or field name). Strings returned by the webservice are converted
+// to the type of the field (e.g. `"42"` to int, `"1"` to bool), so `json:",string"` options are not required.
+func (c *Client) ExecuteQuery(q TypedQuery, rows interface{}) (err error) {
+	rowsValue := reflect.ValueOf(rows)
+	if rowsValue.Kind() != reflect.Ptr || rowsValue.Elem().Kind() != reflect.Slice {
+		return errors.New("rows parameter is not a slice pointer")
+	}
+
+	params, err := queryParams(q)
+	if err != nil {
+		return utilError.FunctionError(q.QueryName() + " - " + err.Error())
+	}
+
+	if err = c.v2.Login(); err != nil {
+		return
+	}
+
+	response := typedQueryResponse{}
+	err = c.v2.Query(q.QueryName(), &response, params)
+	if err != nil {
+		err = utilError.FunctionError(err.Error())
+		log.Error("Error: ", err)
+		return
+	}
+
+	err = decodeRows(response.Data, rowsValue.Elem())
+	if err != nil {
+		return utilError.FunctionError(q.QueryName() + " - " + err.Error())
+	}
+	return
+}
+
+// QueryOne executes the query and decodes the only result row into row, which must be a pointer to a struct.
+//
+// It fails if the query returns no row or more than one row.
+func (c *Client) QueryOne(q TypedQuery, row interface{}) (err error) {
+	return c.queryOne(q, row, q.QueryName())
+}
+
+// queryOne is `QueryOne` with the subject (e.g. the name that was looked up) used in the error messages.
+func (c *Client) queryOne(q TypedQuery, row interface{}, subject string) (err error) {
+	rowValue := reflect.ValueOf(row)
+	if rowValue.Kind() != reflect.Ptr || rowValue.IsNil() {
+		return errors.New("row parameter is not a pointer")
+	}
+
+	rows := reflect.New(reflect.SliceOf(rowValue.Elem().Type()))
+	if err = c.ExecuteQuery(q, rows.Interface()); err != nil {
+		return
+	}
+
+	switch rows.Elem().Len() {
+	case 0:
+		err = utilError.FunctionError(subject + " - " + v2.ErrNoResult.Error())
+	case 1:
+		rowValue.Elem().Set(rows.Elem().Index(0))
+	default:
+		err = utilError.FunctionError(subject + " - " + v2.ErrTooManyResults.Error())
+	}
+
+	return
+}
+
+// queryParams returns the argvN parameters of the fields tagged with `argv:"N"`.
+func queryParams(q TypedQuery) (params map[string]string, err error) {
+	params = map[string]string{}
+
+	value := reflect.ValueOf(q)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		tag := structField.Tag.Get("argv")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		index, convErr := strconv.Atoi(tag)
+		if convErr != nil || index < 1 {
+			return nil, fmt.Errorf("invalid argv tag \"%s\" of field %s", tag, structField.Name)
+		}
+
+		if structField.PkgPath != "" {
+			return nil, fmt.Errorf("argv tag \"%s\" of unexported field %s", tag, structField.Name)
+		}
+
+		name := "argv" + tag
+		if _, exists := params[name]; exists {
+			return nil, fmt.Errorf("duplicate argv tag \"%s\" of field %s", tag, structField.Name)
+		}
+
+		params[name], err = formatQueryParam(value.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", structField.Name, err)
+		}
+	}
+
+	return
+}
+
+func formatQueryParam(value reflect.Value) (string, error) {
+	if value.Type() == reflect.TypeOf(time.Time{}) {
+		return v2.FlexTime{Time: value.Interface().(time.Time).In(v2.FlexTimeLocation)}.String(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return "", nil
+		}
+		return formatQueryParam(value.Elem())
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return convertBoolToString[value.Bool()], nil
+	case reflect.Slice, reflect.Array:
+		items := make([]string, value.Len())
+		for i := range items {
+			item, err := formatQueryParam(value.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return strings.Join(items, ","), nil
+	}
+
+	if !value.CanInterface() {
+		return "", fmt.Errorf("unsupported parameter type %s", value.Type())
+	}
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+	return "", fmt.Errorf("unsupported parameter type %s", value.Type())
+}
+
+// decodeRows decodes the json rows into the slice, elements may be structs or pointers to structs.
+func decodeRows(data json.RawMessage, slice reflect.Value) (err error) {
+	var rawRows []map[string]interface{}
+	if len(data) > 0 && string(data) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err = decoder.Decode(&rawRows); err != nil {
+			return
+		}
+	}
+
+	elemType := slice.Type().Elem()
+	rowType := elemType
+	if rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported row type %s", elemType)
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(rawRows))
+	for _, rawRow := range rawRows {
+		row := reflect.New(rowType)
+		if err = decodeRow(rawRow, row.Elem()); err != nil {
+			return
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			result = reflect.Append(result, row)
+		} else {
+			result = reflect.Append(result, row.Elem())
+		}
+	}
+
+	slice.Set(result)
+	return
+}
+
+func decodeRow(rawRow map[string]interface{}, row reflect.Value) error {
+	for i := 0; i < row.NumField(); i++ {
+		structField := row.Type().Field(i)
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(structField.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+
+		raw, ok := rawRow[name]
+		if !ok || raw == nil {
+			continue
+		}
+
+		if err := decodeField(raw, row.Field(i)); err != nil {
+			return fmt.Errorf("field %s: cannot decode %v: %v", structField.Name, raw, err)
+		}
+	}
+	return nil
+}
+
+// decodeField sets the field to the json value, strings are converted to numbers and booleans.
+func decodeField(raw interface{}, field reflect.Value) (err error) {
+	if unmarshaler, ok := field.Addr().Interface().(json.Unmarshaler); ok {
+		var rawJson []byte
+		if rawJson, err = json.Marshal(raw); err != nil {
+			return
+		}
+		return unmarshaler.UnmarshalJSON(rawJson)
+	}
+
+	text := ""
+	switch rawValue := raw.(type) {
+	case string:
+		text = rawValue
+	case json.Number:
+		text = rawValue.String()
+	case bool:
+		text = strconv.FormatBool(rawValue)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+		if text != "" {
+			parsed, err = strconv.ParseInt(text, 10, 64)
+		}
+		field.SetInt(parsed)
+		return
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var parsed uint64
+		if text != "" {
+			parsed, err = strconv.ParseUint(text, 10, 64)
+		}
+		field.SetUint(parsed)
+		return
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		if text != "" {
+			parsed, err = strconv.ParseFloat(text, 64)
+		}
+		field.SetFloat(parsed)
+		return
+	case reflect.Bool:
+		var parsed bool
+		if text != "" {
+			parsed, err = strconv.ParseBool(text)
+		}
+		field.SetBool(parsed)
+		return
+	}
+
+	rawJson, err := json.Marshal(raw)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(rawJson, field.Addr().Interface())
+}
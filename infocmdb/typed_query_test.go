@@ -0,0 +1,139 @@
+package infocmdb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
+)
+
+type testCiIdsOfCiTypeQuery struct {
+	CiTypeId int `argv:"1"`
+}
+
+func (testCiIdsOfCiTypeQuery) QueryName() string { return "int_getListOfCiIdsOfCiType" }
+
+type testCiIdRow struct {
+	CiId int `json:"ciid"`
+}
+
+type testCiQuery struct {
+	CiId int `argv:"1"`
+}
+
+func (testCiQuery) QueryName() string { return "int_getCi" }
+
+type testCiRow struct {
+	CiId      int    `json:"ci_id"`
+	CiType    string `json:"ci_type"`
+	ProjectId int    `json:"project_id,string"`
+}
+
+func TestClient_ExecuteQuery(t *testing.T) {
+	cmdb := newTestClient(nil)
+
+	var rows []testCiIdRow
+	if err := cmdb.ExecuteQuery(testCiIdsOfCiTypeQuery{CiTypeId: 1}, &rows); err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if want := []testCiIdRow{{1}, {2}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("ExecuteQuery() rows = %v, want %v", rows, want)
+	}
+
+	var pointerRows []*testCiIdRow
+	if err := cmdb.ExecuteQuery(&testCiIdsOfCiTypeQuery{CiTypeId: 1}, &pointerRows); err != nil || len(pointerRows) != 2 {
+		t.Errorf("ExecuteQuery() with pointer rows = %v, %v", pointerRows, err)
+	}
+
+	if err := cmdb.ExecuteQuery(testCiIdsOfCiTypeQuery{CiTypeId: 1}, rows); err == nil {
+		t.Error("ExecuteQuery() expected error for non pointer rows")
+	}
+}
+
+func TestClient_QueryOne(t *testing.T) {
+	cmdb := newTestClient(nil)
+
+	var row testCiRow
+	if err := cmdb.QueryOne(testCiQuery{CiId: 1}, &row); err != nil {
+		t.Fatalf("QueryOne() error = %v", err)
+	}
+	if want := (testCiRow{CiId: 1, CiType: "demo", ProjectId: 4}); row != want {
+		t.Errorf("QueryOne() row = %+v, want %+v", row, want)
+	}
+
+	var idRow testCiIdRow
+	err := cmdb.QueryOne(testCiIdsOfCiTypeQuery{CiTypeId: 1}, &idRow)
+	if err == nil || !strings.Contains(err.Error(), v2.ErrTooManyResults.Error()) {
+		t.Errorf("QueryOne() error = %v, want %v", err, v2.ErrTooManyResults)
+	}
+}
+
+type testParamsQuery struct {
+	Name    string    `argv:"1"`
+	Active  bool      `argv:"2"`
+	CiIds   []int     `argv:"3"`
+	Factor  float64   `argv:"4"`
+	At      time.Time `argv:"5"`
+	Limit   *int      `argv:"6"`
+	Ignored string
+}
+
+func (testParamsQuery) QueryName() string { return "test" }
+
+type testInvalidQuery struct {
+	Name string `argv:"first"`
+}
+
+func (testInvalidQuery) QueryName() string { return "test" }
+
+type testUnexportedQuery struct {
+	name *string `argv:"1"`
+}
+
+func (testUnexportedQuery) QueryName() string { return "test" }
+
+func Test_queryParams(t *testing.T) {
+	limit, name := 10, "demo"
+	tests := []struct {
+		name    string
+		q       TypedQuery
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "all types",
+			q: testParamsQuery{Name: "demo", Active: true, CiIds: []int{1, 2, 3}, Factor: 1.5, Ignored: "x",
+				At: time.Date(2019, 11, 27, 15, 53, 32, 0, v2.FlexTimeLocation)},
+			want: map[string]string{"argv1": "demo", "argv2": "1", "argv3": "1,2,3", "argv4": "1.5", "argv5": "2019-11-27 15:53:32", "argv6": ""},
+		},
+		{
+			name: "pointer",
+			q:    testParamsQuery{Limit: &limit},
+			want: map[string]string{"argv1": "", "argv2": "0", "argv3": "", "argv4": "0", "argv5": "", "argv6": "10"},
+		},
+		{
+			name:    "unexported field",
+			q:       testUnexportedQuery{name: &name},
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag",
+			q:       testInvalidQuery{Name: "demo"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := queryParams(tt.q)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("queryParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queryParams() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,20 @@ package infocmdb
 
 import (
 	"errors"
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/auth"
 	"github.com/infonova/infocmdb-sdk-go/infocmdb/config"
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/transport"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
-	log "github.com/sirupsen/logrus"
 )
 
 var (
 	ErrFailedToCreateInfoCMDB  = errors.New("failed to create infocmdb object")
 	ErrNoCredentials           = errors.New("must provide credentials")
+	ErrApiTokenNotSupported    = errors.New("apiToken is not supported by the v1 api, apiUser and apiPassword are required")
 	ErrNotImplemented          = errors.New("not implemented")
 	ErrNoResult                = errors.New("query returned no result")
 	ErrTooManyResults          = errors.New("query returned to many results, expected one")
@@ -19,16 +23,23 @@ var (
 )
 
 type Config struct {
-	ApiUrl       string `yaml:"apiUrl"`
-	ApiUser      string `yaml:"apiUser"`
-	ApiPassword  string `yaml:"apiPassword"`
+	ApiUrl      string `yaml:"apiUrl"`
+	ApiUser     string `yaml:"apiUser"`
+	ApiPassword string `yaml:"apiPassword"`
+	// Static apikey, if empty an apikey is requested with ApiUser and ApiPassword
 	ApiKey       string
 	CmdbBasePath string `yaml:"CmdbBasePath"`
 }
 
+// Cmdb is safe for concurrent use after the config has been loaded
 type Cmdb struct {
 	Config Config
 	Cache  *cache.Cache
+	// Client used for all requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+
+	authMutex     sync.Mutex
+	authenticator *auth.Authenticator
 }
 
 type CiRelationDirection string
@@ -59,25 +70,99 @@ func New() (i *Cmdb) {
 
 func (i *Cmdb) LoadConfig(config Config) {
 	i.Config = config
+	i.setAuthenticator(nil)
 }
 
+// LoadConfigFile loads the config file and env variables as described in `config.Load`
 func (i *Cmdb) LoadConfigFile(path string) (err error) {
-	err = config.LoadYamlConfig(path, &i.Config)
+	loadedConfig, err := config.Load(path)
 	if err != nil {
 		return err
 	}
 
+	return i.ApplyConfig(loadedConfig)
+}
+
+// ApplyConfig uses the connection and transport settings of an already loaded config
+//
+// The apiToken of the config is only supported by the v2 api, with a token but without apiUser
+// every login of the v1 api fails with ErrApiTokenNotSupported.
+func (i *Cmdb) ApplyConfig(loadedConfig config.Config) (err error) {
+	i.Config.ApiUrl = loadedConfig.ApiUrl
+	i.Config.ApiUser = loadedConfig.ApiUser
+	i.Config.ApiPassword = loadedConfig.ApiPassword
+	i.Config.CmdbBasePath = loadedConfig.CmdbBasePath
+	i.setAuthenticator(nil)
+	if loadedConfig.ApiToken != "" && loadedConfig.ApiUser == "" {
+		i.setAuthenticator(auth.New(func() (auth.Token, error) {
+			return auth.Token{}, ErrApiTokenNotSupported
+		}))
+	}
+
+	transportOptions, err := loadedConfig.TransportOptions()
+	if err != nil {
+		return err
+	}
+	if i.HTTPClient == nil && !transportOptions.IsDefault() {
+		i.HTTPClient, err = transport.NewHTTPClient(transportOptions)
+		if err != nil {
+			return err
+		}
+	} else if i.HTTPClient != nil && i.HTTPClient.Timeout == 0 {
+		// custom transport, only the timeout of the config is applied
+		i.HTTPClient.Timeout = transportOptions.Timeout
+	}
 	return
 }
 
-func (i *Cmdb) Login() error {
-	if i.Config.ApiKey != "" {
-		log.Trace("already logged in")
-		return nil
+// SetTransport replaces the round tripper used for all requests, TLS and proxy settings of the config are ignored.
+func (i *Cmdb) SetTransport(roundTripper http.RoundTripper) {
+	if i.HTTPClient == nil {
+		i.HTTPClient = &http.Client{}
+	}
+	i.HTTPClient.Transport = roundTripper
+}
+
+func (i *Cmdb) httpClient() *http.Client {
+	if i.HTTPClient == nil {
+		return http.DefaultClient
 	}
+	return i.HTTPClient
+}
+
+// Login ensures a valid apikey, it is refreshed automatically before it expires.
+func (i *Cmdb) Login() error {
+	_, err := i.ApiKey()
+	return err
+}
+
+// ApiKey returns a valid apikey, logging in if necessary.
+func (i *Cmdb) ApiKey() (string, error) {
+	return i.getAuthenticator().Token()
+}
+
+// getAuthenticator returns the authenticator, it is created from the config on first use.
+func (i *Cmdb) getAuthenticator() *auth.Authenticator {
+	i.authMutex.Lock()
+	defer i.authMutex.Unlock()
 
-	if i.Config.ApiUser == "" {
-		return ErrNoCredentials
+	if i.authenticator == nil {
+		if i.Config.ApiKey != "" {
+			i.authenticator = auth.NewStatic(i.Config.ApiKey)
+		} else {
+			apiUrl, username, password := i.Config.ApiUrl, i.Config.ApiUser, i.Config.ApiPassword
+			i.authenticator = auth.New(func() (auth.Token, error) {
+				return i.requestApiKey(apiUrl, username, password)
+			})
+		}
 	}
-	return i.LoginWithUserPass(i.Config.ApiUrl, i.Config.ApiUser, i.Config.ApiPassword)
+
+	return i.authenticator
+}
+
+func (i *Cmdb) setAuthenticator(authenticator *auth.Authenticator) {
+	i.authMutex.Lock()
+	defer i.authMutex.Unlock()
+
+	i.authenticator = authenticator
 }
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"testing"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/config"
 	utilTesting "github.com/infonova/infocmdb-sdk-go/util/testing"
 )
 
@@ -57,7 +59,8 @@ func ExampleCmdb_Login() {
 		return
 	}
 
-	fmt.Printf("Login ok, ApiKey(len): %d\n", len(cmdb.Config.ApiKey))
+	apikey, _ := cmdb.ApiKey()
+	fmt.Printf("Login ok, ApiKey(len): %d\n", len(apikey))
 
 	// Output:
 	// Login ok, ApiKey(len): 30
@@ -72,7 +75,8 @@ func ExampleCmdb_Login_with_ApiKey() {
 		return
 	}
 
-	fmt.Printf("Login ok, ApiKey(len): %d\n", len(cmdb.Config.ApiKey))
+	apikey, _ := cmdb.ApiKey()
+	fmt.Printf("Login ok, ApiKey(len): %d\n", len(apikey))
 
 	// Output:
 	// Login ok, ApiKey(len): 30
@@ -97,3 +101,13 @@ func ExampleCmdb_CallWebservice_post_query() {
 	// Output:
 	// Post:  {"status":"OK","data":[{"ciid":"1"},{"ciid":"2"}]}
 }
+
+func TestCmdb_ApplyConfig_apiToken(t *testing.T) {
+	i := New()
+	if err := i.ApplyConfig(config.Config{ApiUrl: "http://localhost", ApiToken: "token"}); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if _, err := i.ApiKey(); err != ErrApiTokenNotSupported {
+		t.Errorf("ApiKey() error = %v, want %v", err, ErrApiTokenNotSupported)
+	}
+}
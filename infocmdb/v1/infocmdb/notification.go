@@ -38,15 +38,15 @@ type NotificationResponse struct {
 
 func (i *Cmdb) SendNotification(notifyName string, params NotifyParams) (resp NotificationResponse, err error) {
 
-	err = i.Login()
+	apikey, err := i.ApiKey()
 	if err != nil {
 		return resp, err
 	}
 
-	httpClient := &http.Client{}
+	httpClient := i.httpClient()
 	reqParams := url.Values{}
 
-	reqParams.Add("apikey", i.Config.ApiKey)
+	reqParams.Add("apikey", apikey)
 
 	for bodyKey, bodyVal := range params.OtherParams {
 		reqParams.Add(bodyKey, bodyVal)
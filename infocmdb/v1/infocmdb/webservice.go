@@ -10,8 +10,12 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/auth"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 )
 
 var (
@@ -23,17 +27,30 @@ type ResultLogin struct {
 	ApiKey string `json:"apikey"`
 }
 
+// LoginWithUserPass logs in with the given credentials, they are used for all further requests.
 func (i *Cmdb) LoginWithUserPass(apiUrl string, username string, password string) error {
+	i.setAuthenticator(auth.New(func() (auth.Token, error) {
+		return i.requestApiKey(apiUrl, username, password)
+	}))
+	return i.Login()
+}
+
+// requestApiKey requests a new apikey without changing the apikey used by the client.
+func (i *Cmdb) requestApiKey(apiUrl string, username string, password string) (token auth.Token, err error) {
+	if username == "" {
+		return token, ErrNoCredentials
+	}
 
 	log.Debugf("Opening new WebClient connection. (Url: %s, Username: %s)", apiUrl, username)
+	redact.AddSecrets(password)
 
-	reqURL := fmt.Sprintf("%s/api/login/username/%s/password/%s/timeout/600/method/json",
-		apiUrl, url.PathEscape(username), url.PathEscape(password))
+	lifetime := auth.DefaultLifetime
+	reqURL := fmt.Sprintf("%s/api/login/username/%s/password/%s/timeout/%d/method/json",
+		apiUrl, url.PathEscape(username), url.PathEscape(password), int(lifetime.Seconds()))
 
-	resp, err := http.Get(reqURL)
+	resp, err := i.httpClient().Get(reqURL)
 	if err != nil {
-		errMsg := strings.Replace(err.Error(), password, "*******", -1)
-		return errors.New(errMsg)
+		return token, errors.New(redact.String(err.Error()))
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -43,27 +60,30 @@ func (i *Cmdb) LoginWithUserPass(apiUrl string, username string, password string
 
 	byteBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return
 	}
 
 	var loginResult ResultLogin
 	err = json.Unmarshal(byteBody, &loginResult)
 	if err != nil {
-		return err
+		return
 	}
 
 	if loginResult.Status != "OK" {
-		return ErrLoginFailed
+		return token, ErrLoginFailed
 	}
 
-	i.Config.ApiKey = loginResult.ApiKey
-	return nil
+	token.Value = loginResult.ApiKey
+	token.ExpiresAt = time.Now().Add(lifetime)
+	return
 }
 
+// LoginWithApiKey uses the given static apikey for all further requests.
 func (i *Cmdb) LoginWithApiKey(url string, apikey string) error {
-	log.Debugf("Opening new WebClient connection using ApiKey. (Url: %s, ApiKey: %s)", url, apikey)
+	log.Debugf("Opening new WebClient connection using ApiKey. (Url: %s, ApiKey: %s)", url, redact.Mask)
 	i.Config.ApiUrl = url
 	i.Config.ApiKey = apikey
+	i.setAuthenticator(auth.NewStatic(apikey))
 	return nil
 }
 
@@ -84,16 +104,14 @@ func (i *Cmdb) CallWebservice(method string, service string, serviceName string,
 		return err
 	}
 
-	if i.Config.ApiKey == "" {
-		err = i.Login()
-		if err != nil {
-			return err
-		}
+	apikey, err := i.ApiKey()
+	if err != nil {
+		return err
 	}
 
-	params.Set("apikey", i.Config.ApiKey)
+	params.Set("apikey", apikey)
 	reqURL := ""
-	httpClient := &http.Client{}
+	httpClient := i.httpClient()
 	var resp *http.Response
 
 	switch method {
@@ -109,7 +127,7 @@ func (i *Cmdb) CallWebservice(method string, service string, serviceName string,
 			return err
 		}
 	case http.MethodGet:
-		reqURL = i.Config.ApiUrl + "/api/adapter/apikey/" + i.Config.ApiKey + "/" + service + "/" + serviceName + "/method/json"
+		reqURL = i.Config.ApiUrl + "/api/adapter/apikey/" + apikey + "/" + service + "/" + serviceName + "/method/json"
 		req, err := http.NewRequest(method, reqURL, nil)
 		if err != nil {
 			return err
@@ -131,7 +149,7 @@ func (i *Cmdb) CallWebservice(method string, service string, serviceName string,
 
 	err = checkResponseStatusMessage(byteBody)
 	if err != nil {
-		err = errors.New(err.Error() + ": " + string(byteBody))
+		err = errors.New(err.Error() + ": " + redact.String(string(byteBody)))
 		return err
 	}
 
@@ -143,7 +161,7 @@ func (i *Cmdb) CallWebservice(method string, service string, serviceName string,
 
 	err = json.Unmarshal([]byte(byteBody), &variable)
 	if err != nil {
-		err = errors.New(err.Error() + ": " + string(byteBody))
+		err = errors.New(err.Error() + ": " + redact.String(string(byteBody)))
 		return err
 	}
 	return nil
@@ -159,7 +177,7 @@ func checkResponseStatusMessage(byteBody []byte) (err error) {
 	err = json.Unmarshal([]byte(byteBody), &responseStatus)
 	if err != nil {
 		log.Error("error checking StatusMessage: ", err)
-		log.Error(string(byteBody))
+		log.Error(redact.String(string(byteBody)))
 		return err
 	}
 
@@ -172,7 +190,7 @@ func checkResponseStatusMessage(byteBody []byte) (err error) {
 // Webservice queries a given webservice with all params supplied
 // Returns err != nil if query fails
 func (i *Cmdb) Webservice(ws string, params url.Values) (r string, err error) {
-	log.Debugf("Webservice: %s, Params: %v", ws, params)
+	log.Debugf("Webservice: %s, Params: %v", ws, redact.Values(params))
 	err = i.CallWebservice(http.MethodPost, "query", ws, params, &r)
 	if err != nil {
 		return "", err
@@ -3,10 +3,15 @@ package client
 import (
 	"errors"
 	"fmt"
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/auth"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/resty.v1"
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type LoginParams struct {
@@ -15,9 +20,14 @@ type LoginParams struct {
 	Lifetime int
 }
 
+// Client of the v2 api, it is safe for concurrent use.
+//
+// Requests share a single login, the token is passed per request and never changed on the underlying resty client.
 type Client struct {
-	resty       *resty.Client
-	loginParams LoginParams
+	resty *resty.Client
+
+	mutex         sync.RWMutex
+	authenticator *auth.Authenticator
 }
 
 // Response is the default json return of the cmdb upon any request success or error
@@ -33,7 +43,7 @@ type ResponseError struct {
 }
 
 func (res ResponseError) Error() string {
-	return fmt.Sprintf("%s\n%+v", res.Message, res.Data)
+	return redact.String(fmt.Sprintf("%s\n%+v", res.Message, res.Data))
 }
 
 func New(baseURL string) (c *Client) {
@@ -43,6 +53,21 @@ func New(baseURL string) (c *Client) {
 	return
 }
 
+// NewWithHTTPClient returns a client sending all requests with the given http client
+func NewWithHTTPClient(baseURL string, httpClient *http.Client) (c *Client) {
+	c = &Client{}
+	c.resty = resty.NewWithClient(httpClient).
+		SetHostURL(baseURL)
+	return
+}
+
+// SetTransport replaces the round tripper of the underlying http client
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	if c.resty != nil {
+		c.resty.SetTransport(transport)
+	}
+}
+
 type loginTokenReturn struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
@@ -51,12 +76,62 @@ type loginTokenReturn struct {
 	} `json:"data"`
 }
 
+// Login sets the credentials used for all requests and returns a valid token.
+//
+// The token is refreshed automatically before it expires.
 func (c *Client) Login(loginParams LoginParams) (token string, err error) {
-	c.loginParams = loginParams
+	c.UseCredentials(loginParams)
+	return c.Token()
+}
 
-	if loginParams.Username == "" || loginParams.Password == "" {
+// UseCredentials sets the credentials used for all requests, the login happens on the first request.
+func (c *Client) UseCredentials(loginParams LoginParams) {
+	c.setAuthenticator(auth.New(func() (auth.Token, error) {
+		return c.RequestToken(loginParams)
+	}))
+}
+
+// SetTokenStore persists the tokens obtained by the credentials of `UseCredentials` or `Login`
+func (c *Client) SetTokenStore(store auth.Store, key string) {
+	if authenticator := c.getAuthenticator(); authenticator != nil {
+		authenticator.SetStore(store, key)
+	}
+}
+
+// SetAuthToken sets a token that has been obtained without `Login`, e.g. a configured api token
+func (c *Client) SetAuthToken(token string) {
+	c.setAuthenticator(auth.NewStatic(token))
+}
+
+// Token returns a valid token, logging in if necessary
+func (c *Client) Token() (token string, err error) {
+	authenticator := c.getAuthenticator()
+	if authenticator == nil {
 		return "", errors.New("must provide credentials")
 	}
+	return authenticator.Token()
+}
+
+func (c *Client) getAuthenticator() *auth.Authenticator {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.authenticator
+}
+
+func (c *Client) setAuthenticator(authenticator *auth.Authenticator) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.authenticator = authenticator
+}
+
+// RequestToken requests a new api token without changing the token used by the client.
+func (c *Client) RequestToken(loginParams LoginParams) (token auth.Token, err error) {
+	if loginParams.Username == "" || loginParams.Password == "" {
+		return token, errors.New("must provide credentials")
+	}
+	redact.AddSecrets(loginParams.Password)
 
 	var loginResult loginTokenReturn
 	params := map[string]string{
@@ -74,20 +149,21 @@ func (c *Client) Login(loginParams LoginParams) (token string, err error) {
 		Post("/apiV2/auth/token")
 
 	if err != nil {
-		return "", err
+		return token, err
 	}
 
 	if resp != nil && resp.IsError() {
-		return "", errResp
+		return token, errResp
 	}
 
-	token = loginResult.Data.Token
-
-	if token == "" {
-		return "", errors.New("login status not ok")
+	if loginResult.Data.Token == "" {
+		return token, errors.New("login status not ok")
 	}
 
-	c.resty.SetAuthToken(token)
+	token.Value = loginResult.Data.Token
+	if loginParams.Lifetime > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(loginParams.Lifetime) * time.Second)
+	}
 	return
 }
 
@@ -96,6 +172,17 @@ type PrepareRequestFunc func(request *resty.Request) *resty.Request
 // Executes a request, automatically resolving timed out API token problems and retrying.
 func (c *Client) Execute(method, url string, prepareRequestFunc PrepareRequestFunc) (resp *resty.Response, err error) {
 	req := prepareRequestFunc(c.resty.NewRequest())
+
+	token := ""
+	authenticator := c.getAuthenticator()
+	if authenticator != nil {
+		token, err = authenticator.Token()
+		if err != nil {
+			return
+		}
+		req.SetAuthToken(token)
+	}
+
 	resp, err = req.Execute(method, url)
 
 	if err != nil {
@@ -104,10 +191,11 @@ func (c *Client) Execute(method, url string, prepareRequestFunc PrepareRequestFu
 
 	if resp.IsError() &&
 		resp.StatusCode() == 403 &&
-		strings.Contains(resp.String(), "Not authenticated") {
+		strings.Contains(resp.String(), "Not authenticated") &&
+		authenticator != nil {
 
 		log.Debug("Request failed due to authentication error, logging in again and retrying...")
-		token, err := c.Login(c.loginParams)
+		token, err := authenticator.Refresh(token)
 		if err != nil {
 			return nil, errors.New("re-login after authentication error failed: " + err.Error())
 		}
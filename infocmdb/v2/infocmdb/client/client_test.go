@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gopkg.in/resty.v1"
+)
+
+func TestClient_Execute_concurrentRelogin(t *testing.T) {
+	var logins int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/apiV2/auth/token" {
+			login := atomic.AddInt64(&logins, 1)
+			fmt.Fprintf(w, `{"success":true,"message":"ok","data":{"token":"token%d"}}`, login)
+			return
+		}
+
+		// the first token is rejected as if it had expired
+		if r.Header.Get("Authorization") == "Bearer token1" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"success":false,"message":"Not authenticated","data":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"message":"ok","data":null}`)
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	if _, err := c.Login(LoginParams{Username: "admin", Password: "admin", Lifetime: 600}); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Execute(resty.MethodGet, "/apiV2/ci", func(request *resty.Request) *resty.Request {
+				return request
+			})
+			if err != nil || resp.IsError() {
+				t.Errorf("Execute() = %v, %v", resp, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins := atomic.LoadInt64(&logins); logins != 2 {
+		t.Errorf("logins = %d, want 2 (initial login and a single re-login)", logins)
+	}
+}
@@ -3,11 +3,13 @@ package infocmdb
 import (
 	"errors"
 	"github.com/infonova/infocmdb-sdk-go/infocmdb/config"
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/transport"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb/client"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 	"github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 )
@@ -16,7 +18,10 @@ type Config struct {
 	Url      string `yaml:"apiUrl"`
 	Username string `yaml:"apiUser"`
 	Password string `yaml:"apiPassword"`
+	Token    string `yaml:"apiToken"`
 	BasePath string `yaml:"BasePath"`
+	// Directory of the persistent token cache, disabled if empty
+	TokenCacheDir string `yaml:"tokenCacheDir"`
 }
 
 type Cmdb struct {
@@ -25,6 +30,8 @@ type Cmdb struct {
 	Client *client.Client
 	Logger *log.Logger
 	Error  error
+	// Client used for all requests, the resty default client if nil
+	HTTPClient *http.Client
 }
 
 type ErrorReturn struct {
@@ -61,31 +68,86 @@ const (
 
 func (cmdb *Cmdb) LoadConfig(config Config) {
 	cmdb.Config = config
-	cmdb.Client = client.New(config.Url)
+	cmdb.Client = cmdb.newClient()
+	cmdb.useConfiguredCredentials()
 }
 
+// SetTransport replaces the round tripper used for all requests, TLS and proxy settings of the config are ignored.
+func (cmdb *Cmdb) SetTransport(roundTripper http.RoundTripper) {
+	if cmdb.HTTPClient == nil {
+		cmdb.HTTPClient = &http.Client{}
+	}
+	cmdb.HTTPClient.Transport = roundTripper
+	cmdb.Client.SetTransport(roundTripper)
+}
+
+func (cmdb *Cmdb) newClient() *client.Client {
+	if cmdb.HTTPClient == nil {
+		return client.New(cmdb.Config.Url)
+	}
+	return client.NewWithHTTPClient(cmdb.Config.Url, cmdb.HTTPClient)
+}
+
+// LoadConfigFile loads the config file and env variables as described in `config.Load`
 func (cmdb *Cmdb) LoadConfigFile(path string) (err error) {
-	err = config.LoadYamlConfig(path, &cmdb.Config)
+	loadedConfig, err := config.Load(path)
 	if err != nil {
 		return
 	}
 
+	return cmdb.ApplyConfig(loadedConfig)
+}
+
+// ApplyConfig uses the connection and transport settings of an already loaded config
+func (cmdb *Cmdb) ApplyConfig(loadedConfig config.Config) (err error) {
+	cmdb.Config = Config{
+		Url:           loadedConfig.ApiUrl,
+		Username:      loadedConfig.ApiUser,
+		Password:      loadedConfig.ApiPassword,
+		Token:         loadedConfig.ApiToken,
+		BasePath:      loadedConfig.BasePath,
+		TokenCacheDir: loadedConfig.TokenCacheDir,
+	}
+
+	transportOptions, err := loadedConfig.TransportOptions()
+	if err != nil {
+		return err
+	}
+	if cmdb.HTTPClient == nil && !transportOptions.IsDefault() {
+		cmdb.HTTPClient, err = transport.NewHTTPClient(transportOptions)
+		if err != nil {
+			return err
+		}
+	} else if cmdb.HTTPClient != nil && cmdb.HTTPClient.Timeout == 0 {
+		// custom transport, only the timeout of the config is applied
+		cmdb.HTTPClient.Timeout = transportOptions.Timeout
+	}
+
 	err = cmdb.applyUrlFromRedirect()
 	if err != nil {
 		return
 	}
 
-	log.Debugf("Config after applied url from redirect: %+v", cmdb.Config)
-	cmdb.Client = client.New(cmdb.Config.Url)
+	log.Debugf("Config after applied url from redirect: %s", redact.Struct(cmdb.Config))
+	cmdb.Client = cmdb.newClient()
+	cmdb.useConfiguredCredentials()
 	return
 }
 
 func (cmdb *Cmdb) applyUrlFromRedirect() (err error) {
+	if cmdb.Config.Url == "" {
+		return errors.New("missing api url (apiUrl)")
+	}
+
 	c := &http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
+	if cmdb.HTTPClient != nil {
+		c.Transport = cmdb.HTTPClient.Transport
+		c.Timeout = cmdb.HTTPClient.Timeout
+	}
 	resp, err := c.Get(cmdb.Config.Url)
 	if err != nil {
 		return
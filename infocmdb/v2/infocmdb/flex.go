@@ -0,0 +1,175 @@
+package infocmdb
+
+// Tolerant json types for the stringly-typed values returned by the infoCMDB.
+//
+// Numbers and booleans are usually returned as strings ("42", "0"/"1"), missing values as null or empty string.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Layouts of timestamps returned by the infoCMDB, tried in order.
+var flexTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// Timezone of timestamps without zone information.
+var FlexTimeLocation = time.Local
+
+var jsonNull = []byte("null")
+
+// unquote returns the content of a json string or the raw value, null is returned as empty string.
+func unquote(b []byte) (string, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, jsonNull) {
+		return "", nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		err := json.Unmarshal(b, &s)
+		return s, err
+	}
+	return string(b), nil
+}
+
+// FlexInt decodes numbers, numeric strings, empty strings and null (as 0).
+type FlexInt int
+
+func (i *FlexInt) UnmarshalJSON(b []byte) error {
+	s, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		*i = 0
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid int %s", b)
+	}
+	*i = FlexInt(parsed)
+	return nil
+}
+
+func (i FlexInt) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(i))), nil
+}
+
+// Int returns the value as int.
+func (i FlexInt) Int() int {
+	return int(i)
+}
+
+// FlexBool decodes booleans, "0"/"1", "true"/"false", numbers, empty strings and null (as false).
+type FlexBool bool
+
+func (f *FlexBool) UnmarshalJSON(b []byte) error {
+	s, err := unquote(b)
+	if err != nil {
+		return err
+	}
+
+	switch s {
+	case "", "0", "false":
+		*f = false
+	case "1", "true":
+		*f = true
+	default:
+		number, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid bool %s", b)
+		}
+		*f = number != 0
+	}
+	return nil
+}
+
+func (f FlexBool) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatBool(bool(f))), nil
+}
+
+// Bool returns the value as bool.
+func (f FlexBool) Bool() bool {
+	return bool(f)
+}
+
+// FlexTime decodes timestamps like "2019-11-27 15:53:32" in `FlexTimeLocation`,
+// empty strings, null and "0000-00-00 00:00:00" are decoded as zero time.
+type FlexTime struct {
+	time.Time
+}
+
+// ParseFlexTime parses a timestamp in one of the formats returned by the infoCMDB.
+func ParseFlexTime(s string) (t FlexTime, err error) {
+	if s == "" || s == "0000-00-00 00:00:00" || s == "0000-00-00" {
+		return
+	}
+
+	for _, layout := range flexTimeLayouts {
+		var parsed time.Time
+		if parsed, err = time.ParseInLocation(layout, s, FlexTimeLocation); err == nil {
+			return FlexTime{parsed}, nil
+		}
+	}
+	return t, fmt.Errorf("invalid time \"%s\"", s)
+}
+
+func (t *FlexTime) UnmarshalJSON(b []byte) error {
+	s, err := unquote(b)
+	if err != nil {
+		return err
+	}
+
+	*t, err = ParseFlexTime(s)
+	return err
+}
+
+func (t FlexTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(t.Format(flexTimeLayouts[0]))
+}
+
+// String returns the time in the format of the infoCMDB, empty for zero time.
+func (t FlexTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(flexTimeLayouts[0])
+}
+
+// NullString distinguishes null (Valid is false) from empty strings, numbers are decoded as their string.
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), jsonNull) {
+		*n = NullString{}
+		return nil
+	}
+
+	s, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	*n = NullString{String: s, Valid: true}
+	return nil
+}
+
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.String)
+}
@@ -0,0 +1,145 @@
+package infocmdb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFlexInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    FlexInt
+		wantErr bool
+	}{
+		{"number", `42`, 42, false},
+		{"string", `"42"`, 42, false},
+		{"negative string", `"-1"`, -1, false},
+		{"empty string", `""`, 0, false},
+		{"null", `null`, 0, false},
+		{"invalid", `"abc"`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FlexInt
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexBool_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    FlexBool
+		wantErr bool
+	}{
+		{"true", `true`, true, false},
+		{"false", `false`, false, false},
+		{"string 1", `"1"`, true, false},
+		{"string 0", `"0"`, false, false},
+		{"string true", `"true"`, true, false},
+		{"number", `2`, true, false},
+		{"empty string", `""`, false, false},
+		{"null", `null`, false, false},
+		{"invalid", `"yes"`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FlexBool
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexTime_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"timestamp", `"2019-11-27 15:53:32"`, time.Date(2019, 11, 27, 15, 53, 32, 0, FlexTimeLocation), false},
+		{"date", `"2019-11-27"`, time.Date(2019, 11, 27, 0, 0, 0, 0, FlexTimeLocation), false},
+		{"rfc3339", `"2019-11-27T15:53:32Z"`, time.Date(2019, 11, 27, 15, 53, 32, 0, time.UTC), false},
+		{"zero date", `"0000-00-00 00:00:00"`, time.Time{}, false},
+		{"empty string", `""`, time.Time{}, false},
+		{"null", `null`, time.Time{}, false},
+		{"invalid", `"yesterday"`, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FlexTime
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullString_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want NullString
+	}{
+		{"string", `"text"`, NullString{String: "text", Valid: true}},
+		{"empty string", `""`, NullString{String: "", Valid: true}},
+		{"number", `12`, NullString{String: "12", Valid: true}},
+		{"null", `null`, NullString{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got NullString
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlex_MarshalJSON(t *testing.T) {
+	value := struct {
+		Int    FlexInt    `json:"int"`
+		Bool   FlexBool   `json:"bool"`
+		Time   FlexTime   `json:"time"`
+		Zero   FlexTime   `json:"zero"`
+		String NullString `json:"string"`
+		Null   NullString `json:"null"`
+	}{
+		Int:    7,
+		Bool:   true,
+		Time:   FlexTime{time.Date(2019, 11, 27, 15, 53, 32, 0, FlexTimeLocation)},
+		String: NullString{String: "text", Valid: true},
+	}
+
+	got, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"int":7,"bool":true,"time":"2019-11-27 15:53:32","zero":null,"string":"text","null":null}`
+	if string(got) != want {
+		t.Errorf("Marshal() got = %s, want %s", got, want)
+	}
+}
@@ -1,29 +1,30 @@
 package infocmdb
 
 import (
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/auth"
 	"github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb/client"
-	utilCache "github.com/patrickmn/go-cache"
-	log "github.com/sirupsen/logrus"
 )
 
+// Login ensures a valid token, it is refreshed automatically before it expires.
 func (cmdb *Cmdb) Login() (err error) {
-	cacheKey := "LoggedIn"
-	_, alreadyLoggedIn := cmdb.Cache.Get(cacheKey)
+	_, err = cmdb.Client.Token()
+	return
+}
 
-	if alreadyLoggedIn {
-		log.Trace("already logged in")
-		return nil
+// useConfiguredCredentials sets the credentials of the config, a configured api token is used if no username is set.
+func (cmdb *Cmdb) useConfiguredCredentials() {
+	if cmdb.Config.Token != "" && cmdb.Config.Username == "" {
+		cmdb.Client.SetAuthToken(cmdb.Config.Token)
+		return
 	}
 
-	_, err = cmdb.Client.Login(client.LoginParams{
+	cmdb.Client.UseCredentials(client.LoginParams{
 		Username: cmdb.Config.Username,
 		Password: cmdb.Config.Password,
-		Lifetime: 600,
+		Lifetime: int(auth.DefaultLifetime.Seconds()),
 	})
-	if err != nil {
-		return
-	}
 
-	cmdb.Cache.Set(cacheKey, true, utilCache.NoExpiration)
-	return
+	if cmdb.Config.TokenCacheDir != "" {
+		cmdb.Client.SetTokenStore(auth.NewFileStore(cmdb.Config.TokenCacheDir), auth.StoreKey(cmdb.Config.Url, cmdb.Config.Username))
+	}
 }
@@ -37,6 +37,11 @@ func TestInfoCMDB_LoginWithUserPass(t *testing.T) {
 			fields{Config: Config{Url: url, Username: "admin", Password: ""}},
 			true,
 		},
+		{
+			"valid api token without credentials",
+			fields{Config: Config{Url: url, Token: "api-token"}},
+			false,
+		},
 		{
 			"invalid no data",
 			fields{Config: Config{Url: url, Username: "", Password: ""}},
@@ -2,6 +2,7 @@ package infocmdb
 
 import (
 	"github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb/client"
+	"github.com/infonova/infocmdb-sdk-go/util/redact"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/resty.v1"
 )
@@ -15,7 +16,7 @@ type queryRequest struct {
 }
 
 func (cmdb *Cmdb) Query(query string, out interface{}, params map[string]string) (err error) {
-	log.Debugf("Querying webservice %v with params %v", query, params)
+	log.Debugf("Querying webservice %v with params %v", query, redact.Params(params))
 
 	if err = cmdb.Login(); err != nil {
 		return
@@ -42,12 +43,15 @@ func (cmdb *Cmdb) Query(query string, out interface{}, params map[string]string)
 	}
 
 	if resp.IsError() {
-		log.Debugf("Status: %v, Error result: %v", resp.StatusCode(), respError)
+		log.Debugf("Status: %v, Error result: %v", resp.StatusCode(), respError.Error())
 		return respError
 	}
 
-	log.Debugf("Response: %s", resp.String())
-	log.Debugf("Mapped result: %+v", out)
+	// masking runs regular expressions over the whole response, skip it if it isn't logged anyway
+	if log.IsLevelEnabled(log.DebugLevel) {
+		log.Debugf("Response: %s", redact.String(resp.String()))
+		log.Debugf("Mapped result: %s", redact.Struct(out))
+	}
 	return
 }
 
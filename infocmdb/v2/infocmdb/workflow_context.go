@@ -3,7 +3,6 @@ package infocmdb
 import (
 	"encoding/json"
 	"errors"
-	log "github.com/sirupsen/logrus"
 	"strconv"
 )
 
@@ -51,71 +50,71 @@ type Data struct {
 type CiDetail struct {
 	//Relations  map[int]Relation          `json:"relations"`
 	Projects   map[int]Project           `json:"projects"`
-	CiTypeID   string                    `json:"ciTypeId"`
+	CiTypeID   FlexInt                   `json:"ciTypeId"`
 	CiTypeName string                    `json:"ciTypeName"`
 	Attributes map[int]map[int]Attribute `json:"attributes"`
 }
 type Relation struct {
-	CiId1            string  `json:"ci_id_1"`
-	CiId2            string  `json:"ci_id_2"`
-	RelationTypeId   string  `json:"relation_type_id"`
-	Direction        string  `json:"direction"`
-	RelationTypeName string  `json:"relation_type_name"`
-	DirectionName    *string `json:"direction_name"`
+	CiId1            FlexInt    `json:"ci_id_1"`
+	CiId2            FlexInt    `json:"ci_id_2"`
+	RelationTypeId   FlexInt    `json:"relation_type_id"`
+	Direction        FlexInt    `json:"direction"`
+	RelationTypeName string     `json:"relation_type_name"`
+	DirectionName    NullString `json:"direction_name"`
 }
 type Project struct {
-	ID                 string `json:"id"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	Note               string `json:"note"`
-	OrderNumber        string `json:"order_number"`
-	IsActive           string `json:"is_active"`
-	UserID             string `json:"user_id"`
-	ValidFrom          string `json:"valid_from"`
-	CiProjectValidFrom string `json:"ci_project_valid_from"`
-	CiProjectHistoryID string `json:"ci_project_history_id"`
+	ID                 FlexInt  `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	Note               string   `json:"note"`
+	OrderNumber        FlexInt  `json:"order_number"`
+	IsActive           FlexBool `json:"is_active"`
+	UserID             FlexInt  `json:"user_id"`
+	ValidFrom          FlexTime `json:"valid_from"`
+	CiProjectValidFrom FlexTime `json:"ci_project_valid_from"`
+	CiProjectHistoryID FlexInt  `json:"ci_project_history_id"`
 }
 type Attribute struct {
-	ID                   string `json:"id"`
-	Name                 string `json:"name"`
-	Description          string `json:"description"`
-	Note                 string `json:"note"`
-	Hint                 string `json:"hint"`
-	AttributeTypeID      string `json:"attribute_type_id"`
-	AttributeGroupID     string `json:"attribute_group_id"`
-	OrderNumber          string `json:"order_number"`
-	Column               string `json:"column"`
-	IsUnique             string `json:"is_unique"`
-	IsNumeric            string `json:"is_numeric"`
-	IsBold               string `json:"is_bold"`
-	IsEvent              string `json:"is_event"`
-	IsUniqueCheck        string `json:"is_unique_check"`
-	IsAutocomplete       string `json:"is_autocomplete"`
-	IsMultiselect        string `json:"is_multiselect"`
-	IsProjectRestricted  string `json:"is_project_restricted"`
-	Regex                string `json:"regex"`
-	WorkflowID           int    `json:"workflow_id,string"`
-	ScriptName           string `json:"script_name"`
-	Tag                  string `json:"tag"`
-	InputMaxlength       string `json:"input_maxlength"`
-	TextareaCols         string `json:"textarea_cols"`
-	TextareaRows         string `json:"textarea_rows"`
-	IsActive             string `json:"is_active"`
-	UserID               string `json:"user_id"`
-	ValidFrom            string `json:"valid_from"`
-	Historicize          string `json:"historicize"`
-	DisplayStyle         string `json:"display_style"`
-	AttributeTypeName    string `json:"attributeTypeName"`
-	AttributeGroup       string `json:"attribute_group"`
-	ParentAttributeGroup string `json:"parent_attribute_group"`
-	ValueText            string `json:"value_text"`
-	ValueDate            string `json:"value_date"`
-	ValueCi              string `json:"value_ci"`
-	CiAttributeID        string `json:"ciAttributeId"`
-	Initial              string `json:"initial"`
-	ValueNote            string `json:"valueNote"`
-	HistoryID            string `json:"history_id"`
-	ValueDefault         string `json:"value_default"`
+	ID                   FlexInt    `json:"id"`
+	Name                 string     `json:"name"`
+	Description          string     `json:"description"`
+	Note                 string     `json:"note"`
+	Hint                 string     `json:"hint"`
+	AttributeTypeID      FlexInt    `json:"attribute_type_id"`
+	AttributeGroupID     FlexInt    `json:"attribute_group_id"`
+	OrderNumber          FlexInt    `json:"order_number"`
+	Column               FlexInt    `json:"column"`
+	IsUnique             FlexBool   `json:"is_unique"`
+	IsNumeric            FlexBool   `json:"is_numeric"`
+	IsBold               FlexBool   `json:"is_bold"`
+	IsEvent              FlexBool   `json:"is_event"`
+	IsUniqueCheck        FlexBool   `json:"is_unique_check"`
+	IsAutocomplete       FlexBool   `json:"is_autocomplete"`
+	IsMultiselect        FlexInt    `json:"is_multiselect"`
+	IsProjectRestricted  FlexBool   `json:"is_project_restricted"`
+	Regex                NullString `json:"regex"`
+	WorkflowID           FlexInt    `json:"workflow_id"`
+	ScriptName           NullString `json:"script_name"`
+	Tag                  string     `json:"tag"`
+	InputMaxlength       FlexInt    `json:"input_maxlength"`
+	TextareaCols         FlexInt    `json:"textarea_cols"`
+	TextareaRows         FlexInt    `json:"textarea_rows"`
+	IsActive             FlexBool   `json:"is_active"`
+	UserID               FlexInt    `json:"user_id"`
+	ValidFrom            FlexTime   `json:"valid_from"`
+	Historicize          FlexBool   `json:"historicize"`
+	DisplayStyle         NullString `json:"display_style"`
+	AttributeTypeName    string     `json:"attributeTypeName"`
+	AttributeGroup       string     `json:"attribute_group"`
+	ParentAttributeGroup string     `json:"parent_attribute_group"`
+	ValueText            NullString `json:"value_text"`
+	ValueDate            FlexTime   `json:"value_date"`
+	ValueCi              FlexInt    `json:"value_ci"`
+	CiAttributeID        FlexInt    `json:"ciAttributeId"`
+	Initial              FlexBool   `json:"initial"`
+	ValueNote            NullString `json:"valueNote"`
+	HistoryID            FlexInt    `json:"history_id"`
+	ValueDefault         NullString `json:"value_default"`
 }
 
 func (ciDetail *CiDetail) GetFirstAttributeByName(name string) *Attribute {
@@ -136,7 +135,7 @@ func (ciDetail *CiDetail) GetFirstAttributeValueTextByName(name string) (string,
 		return "", false
 	}
 
-	return attribute.ValueText, true
+	return attribute.ValueText.String, true
 }
 
 func (ciDetail *CiDetail) GetFirstAttributeValueCiByName(name string) (int, bool) {
@@ -145,12 +144,7 @@ func (ciDetail *CiDetail) GetFirstAttributeValueCiByName(name string) (int, bool
 		return 0, false
 	}
 
-	ciId, err := strconv.Atoi(attribute.ValueCi)
-	if err != nil {
-		log.Fatalf("failed to convert ValueCi of \"%s\" to int: %s", name, err.Error())
-	}
-
-	return ciId, true
+	return int(attribute.ValueCi), true
 }
 
 type getWorkflowContextResponse struct {
@@ -6,6 +6,14 @@ import (
 	"testing"
 )
 
+func testFlexTime(s string) FlexTime {
+	t, err := ParseFlexTime(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
 func TestUnmarshalWorkflowContext(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -33,63 +41,63 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-11-27 15:53:32",
-								CiProjectValidFrom: "2019-11-28 12:23:27",
-								CiProjectHistoryID: "33",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-11-27 15:53:32"),
+								CiProjectValidFrom: testFlexTime("2019-11-28 12:23:27"),
+								CiProjectHistoryID: 33,
 							},
 						},
-						CiTypeID:   "3",
+						CiTypeID:   3,
 						CiTypeName: "res_github_repo",
 						Attributes: map[int]map[int]Attribute{
 							1: {
 								6: Attribute{
-									ID:                   "1",
+									ID:                   1,
 									Name:                 "res_github_repo_name",
 									Description:          "Name",
 									Note:                 "",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "2",
-									OrderNumber:          "20",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     2,
+									OrderNumber:          20,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           1,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-11-28 12:23:27",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-11-28 12:23:27"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "demo",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "6",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "32",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "demo", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        6,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            32,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
@@ -119,151 +127,151 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-11-27 15:53:32",
-								CiProjectValidFrom: "2019-12-02 10:30:17",
-								CiProjectHistoryID: "78",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-11-27 15:53:32"),
+								CiProjectValidFrom: testFlexTime("2019-12-02 10:30:17"),
+								CiProjectHistoryID: 78,
 							},
 						},
-						CiTypeID:   "6",
+						CiTypeID:   6,
 						CiTypeName: "res_jira_issue",
 						Attributes: map[int]map[int]Attribute{
 							6: {
 								30: Attribute{
-									ID:                   "6",
+									ID:                   6,
 									Name:                 "res_jira_issue_project_key",
 									Description:          "Projekt",
 									Note:                 "JIRA Issue Projekt Key",
 									Hint:                 "",
-									AttributeTypeID:      "21",
-									AttributeGroupID:     "2",
-									OrderNumber:          "20",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "1",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      21,
+									AttributeGroupID:     2,
+									OrderNumber:          20,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       true,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "",
-									TextareaCols:         "180",
-									TextareaRows:         "",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-12-02 10:30:17",
-									Historicize:          "1",
-									DisplayStyle:         "optionList",
+									InputMaxlength:       0,
+									TextareaCols:         180,
+									TextareaRows:         0,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-12-02 10:30:17"),
+									Historicize:          true,
+									DisplayStyle:         NullString{String: "optionList", Valid: true},
 									AttributeTypeName:    "selectQuery",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "",
-									ValueDate:            "",
-									ValueCi:              "11",
-									CiAttributeID:        "30",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "77",
-									ValueDefault:         "",
+									ValueText:            NullString{},
+									ValueDate:            FlexTime{},
+									ValueCi:              11,
+									CiAttributeID:        30,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            77,
+									ValueDefault:         NullString{},
 								},
 							},
 							8: {
 								31: Attribute{
-									ID:                   "8",
+									ID:                   8,
 									Name:                 "res_jira_issue_issuetype_name",
 									Description:          "Typ",
 									Note:                 "JIRA Issue Typ Name",
 									Hint:                 "",
-									AttributeTypeID:      "16",
-									AttributeGroupID:     "2",
-									OrderNumber:          "30",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "1",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      16,
+									AttributeGroupID:     2,
+									OrderNumber:          30,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       true,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "",
-									TextareaCols:         "180",
-									TextareaRows:         "",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-12-02 10:30:17",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         180,
+									TextareaRows:         0,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-12-02 10:30:17"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "ciType",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "",
-									ValueDate:            "",
-									ValueCi:              "13",
-									CiAttributeID:        "31",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "77",
-									ValueDefault:         "",
+									ValueText:            NullString{},
+									ValueDate:            FlexTime{},
+									ValueCi:              13,
+									CiAttributeID:        31,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            77,
+									ValueDefault:         NullString{},
 								},
 							},
 							5: {
 								32: Attribute{
-									ID:                   "5",
+									ID:                   5,
 									Name:                 "res_jira_issue_summary",
 									Description:          "Zusammenfassung",
 									Note:                 "JIRA Issue Zusammenfassung",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "2",
-									OrderNumber:          "50",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     2,
+									OrderNumber:          50,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-12-02 10:30:17",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-12-02 10:30:17"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "Demo",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "32",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "77",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "Demo", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        32,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            77,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
@@ -293,63 +301,63 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-11-27 15:53:32",
-								CiProjectValidFrom: "2019-11-27 16:43:14",
-								CiProjectHistoryID: "6",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-11-27 15:53:32"),
+								CiProjectValidFrom: testFlexTime("2019-11-27 16:43:14"),
+								CiProjectHistoryID: 6,
 							},
 						},
-						CiTypeID:   "3",
+						CiTypeID:   3,
 						CiTypeName: "res_github_repo",
 						Attributes: map[int]map[int]Attribute{
 							1: {
 								1: Attribute{
-									ID:                   "1",
+									ID:                   1,
 									Name:                 "res_github_repo_name",
 									Description:          "Name",
 									Note:                 "",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "2",
-									OrderNumber:          "20",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     2,
+									OrderNumber:          20,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-11-28 09:04:31",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-11-28 09:04:31"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "demo2",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "1",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "7",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "demo2", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        1,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            7,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
@@ -358,63 +366,63 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-11-27 15:53:32",
-								CiProjectValidFrom: "2019-11-27 16:43:14",
-								CiProjectHistoryID: "6",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-11-27 15:53:32"),
+								CiProjectValidFrom: testFlexTime("2019-11-27 16:43:14"),
+								CiProjectHistoryID: 6,
 							},
 						},
-						CiTypeID:   "3",
+						CiTypeID:   3,
 						CiTypeName: "res_github_repo",
 						Attributes: map[int]map[int]Attribute{
 							1: {
 								1: Attribute{
-									ID:                   "1",
+									ID:                   1,
 									Name:                 "res_github_repo_name",
 									Description:          "Name",
 									Note:                 "",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "2",
-									OrderNumber:          "20",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     2,
+									OrderNumber:          20,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-11-28 09:58:07",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-11-28 09:58:07"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "demo",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "1",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "8",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "demo", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        1,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            8,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
@@ -444,63 +452,63 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-11-27 15:53:32",
-								CiProjectValidFrom: "2019-11-27 16:43:14",
-								CiProjectHistoryID: "6",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-11-27 15:53:32"),
+								CiProjectValidFrom: testFlexTime("2019-11-27 16:43:14"),
+								CiProjectHistoryID: 6,
 							},
 						},
-						CiTypeID:   "3",
+						CiTypeID:   3,
 						CiTypeName: "res_github_repo",
 						Attributes: map[int]map[int]Attribute{
 							1: {
 								1: Attribute{
-									ID:                   "1",
+									ID:                   1,
 									Name:                 "res_github_repo_name",
 									Description:          "Name",
 									Note:                 "",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "2",
-									OrderNumber:          "20",
-									Column:               "1",
-									IsUnique:             "0",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "0",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     2,
+									OrderNumber:          20,
+									Column:               1,
+									IsUnique:             false,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        false,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-11-28 09:58:07",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-11-28 09:58:07"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Resource Information",
 									ParentAttributeGroup: "0",
-									ValueText:            "demo",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "1",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "8",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "demo", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        1,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            8,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
@@ -530,75 +538,75 @@ func TestUnmarshalWorkflowContext(t *testing.T) {
 						//Relations: map[int]Relation{},
 						Projects: map[int]Project{
 							1: {
-								ID:                 "1",
+								ID:                 1,
 								Name:               "General",
 								Description:        "General",
 								Note:               "General",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-07-08 08:38:11",
-								CiProjectValidFrom: "2019-12-03 13:12:12",
-								CiProjectHistoryID: "461812",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-07-08 08:38:11"),
+								CiProjectValidFrom: testFlexTime("2019-12-03 13:12:12"),
+								CiProjectHistoryID: 461812,
 							},
 							22: {
-								ID:                 "22",
+								ID:                 22,
 								Name:               "T2",
 								Description:        "t2",
 								Note:               "t2",
-								OrderNumber:        "10",
-								IsActive:           "1",
-								UserID:             "1",
-								ValidFrom:          "2019-12-03 13:44:25",
-								CiProjectValidFrom: "2019-12-03 13:44:25",
-								CiProjectHistoryID: "461874",
+								OrderNumber:        10,
+								IsActive:           true,
+								UserID:             1,
+								ValidFrom:          testFlexTime("2019-12-03 13:44:25"),
+								CiProjectValidFrom: testFlexTime("2019-12-03 13:44:25"),
+								CiProjectHistoryID: 461874,
 							},
 						},
-						CiTypeID:   "8",
+						CiTypeID:   8,
 						CiTypeName: "project",
 						Attributes: map[int]map[int]Attribute{
 							11: {
 								167946: Attribute{
-									ID:                   "11",
+									ID:                   11,
 									Name:                 "project_name",
 									Description:          "Name",
 									Note:                 "",
 									Hint:                 "",
-									AttributeTypeID:      "1",
-									AttributeGroupID:     "9",
-									OrderNumber:          "10",
-									Column:               "1",
-									IsUnique:             "1",
-									IsNumeric:            "0",
-									IsBold:               "0",
-									IsEvent:              "0",
-									IsUniqueCheck:        "1",
-									IsAutocomplete:       "0",
-									IsMultiselect:        "0",
-									IsProjectRestricted:  "0",
-									Regex:                "",
+									AttributeTypeID:      1,
+									AttributeGroupID:     9,
+									OrderNumber:          10,
+									Column:               1,
+									IsUnique:             true,
+									IsNumeric:            false,
+									IsBold:               false,
+									IsEvent:              false,
+									IsUniqueCheck:        true,
+									IsAutocomplete:       false,
+									IsMultiselect:        0,
+									IsProjectRestricted:  false,
+									Regex:                NullString{String: "", Valid: true},
 									WorkflowID:           0,
-									ScriptName:           "",
+									ScriptName:           NullString{},
 									Tag:                  "",
-									InputMaxlength:       "0",
-									TextareaCols:         "30",
-									TextareaRows:         "3",
-									IsActive:             "1",
-									UserID:               "1",
-									ValidFrom:            "2019-12-03 13:12:12",
-									Historicize:          "1",
-									DisplayStyle:         "",
+									InputMaxlength:       0,
+									TextareaCols:         30,
+									TextareaRows:         3,
+									IsActive:             true,
+									UserID:               1,
+									ValidFrom:            testFlexTime("2019-12-03 13:12:12"),
+									Historicize:          true,
+									DisplayStyle:         NullString{},
 									AttributeTypeName:    "input",
 									AttributeGroup:       "Project",
 									ParentAttributeGroup: "0",
-									ValueText:            "T2",
-									ValueDate:            "",
-									ValueCi:              "",
-									CiAttributeID:        "167946",
-									Initial:              "1",
-									ValueNote:            "",
-									HistoryID:            "461811",
-									ValueDefault:         "",
+									ValueText:            NullString{String: "T2", Valid: true},
+									ValueDate:            FlexTime{},
+									ValueCi:              0,
+									CiAttributeID:        167946,
+									Initial:              true,
+									ValueNote:            NullString{},
+									HistoryID:            461811,
+									ValueDefault:         NullString{},
 								},
 							},
 						},
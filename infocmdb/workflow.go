@@ -3,9 +3,10 @@ package infocmdb
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
-	"strconv"
 
+	"github.com/infonova/infocmdb-sdk-go/infocmdb/config"
 	v2 "github.com/infonova/infocmdb-sdk-go/infocmdb/v2/infocmdb"
 	log "github.com/sirupsen/logrus"
 )
@@ -39,14 +40,86 @@ type WorkflowParamsHelper struct {
 	UserId              IntWrapper `json:"user_id"`
 }
 
-type IntWrapper int
+// IntWrapper decodes numbers and numeric strings.
+//
+// Deprecated: use v2.FlexInt
+type IntWrapper = v2.FlexInt
 
 // User defined workflow function that can be passed to `workflow.Run`.
 type WorkflowFunc func(params WorkflowParams, cmdb *Client) (err error)
 
+// User defined workflow function that can be passed to `workflow.RunWithResult`.
+type WorkflowResultFunc func(params WorkflowParams, cmdb *Client) WorkflowResult
+
+// Overall status of a workflow execution.
+type WorkflowStatus string
+
+const (
+	WORKFLOW_STATUS_SUCCESS               WorkflowStatus = "SUCCESS"
+	WORKFLOW_STATUS_SUCCESS_WITH_WARNINGS WorkflowStatus = "SUCCESS_WITH_WARNINGS"
+	WORKFLOW_STATUS_FAILED                WorkflowStatus = "FAILED"
+)
+
+// Result of a workflow execution, reported by `workflow.Run` and `workflow.RunWithResult`
+// as final log line and process exit code.
+type WorkflowResult struct {
+	Status  WorkflowStatus
+	Message string
+}
+
+// Successful workflow execution.
+func WorkflowSuccess(message string) WorkflowResult {
+	return WorkflowResult{Status: WORKFLOW_STATUS_SUCCESS, Message: message}
+}
+
+// Successful workflow execution that needs attention.
+func WorkflowSuccessWithWarnings(message string) WorkflowResult {
+	return WorkflowResult{Status: WORKFLOW_STATUS_SUCCESS_WITH_WARNINGS, Message: message}
+}
+
+// Failed workflow execution.
+func WorkflowFailed(message string) WorkflowResult {
+	return WorkflowResult{Status: WORKFLOW_STATUS_FAILED, Message: message}
+}
+
+// ExitCode returns the process exit code of the result.
+// Only failures (and unknown statuses) lead to a non-zero exit code, so that warnings don't mark the workflow as failed.
+func (r WorkflowResult) ExitCode() int {
+	switch r.Status {
+	case WORKFLOW_STATUS_SUCCESS, WORKFLOW_STATUS_SUCCESS_WITH_WARNINGS:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func (r WorkflowResult) String() string {
+	if r.Message == "" {
+		return "Workflow result: " + string(r.Status)
+	}
+	return fmt.Sprintf("Workflow result: %s - %s", r.Status, r.Message)
+}
+
+// withLoggedWarnings downgrades a successful result if any warnings have been logged.
+func (r WorkflowResult) withLoggedWarnings(warnings int) WorkflowResult {
+	if r.Status != WORKFLOW_STATUS_SUCCESS || warnings == 0 {
+		return r
+	}
+
+	message := fmt.Sprintf("%d warning(s) logged", warnings)
+	if r.Message != "" {
+		message = r.Message + ", " + message
+	}
+	return WorkflowSuccessWithWarnings(message)
+}
+
+// exit terminates the process, replaceable for tests.
+var exit = os.Exit
+
 // Helper struct that encapsulates everything that is necessary to run or test a workflow.
 type Workflow struct {
-	config string
+	config         string
+	checkReachable bool
 }
 
 // Creates a new workflow with default configuration.
@@ -61,6 +134,12 @@ func (w *Workflow) SetConfig(config string) {
 	w.config = config
 }
 
+// Enables a check that the api host is reachable before the workflow is started.
+// Disabled by default, the first request fails anyway if the host is not reachable.
+func (w *Workflow) SetCheckReachable(checkReachable bool) {
+	w.checkReachable = checkReachable
+}
+
 // Executes a workflow.
 //
 // First a infoCMDB client instance is created and the workflow parameters are parsed.
@@ -72,21 +151,80 @@ func (w *Workflow) SetConfig(config string) {
 //
 // Any errors that are returned from the workflow function will be logged and lead to a execution failure.
 // Additionally the workflow will be marked as failed when something is printed to Stderr during execution.
+//
+// The result is reported as described in `workflow.RunWithResult`,
+// a workflow that logged warnings finishes with status SUCCESS_WITH_WARNINGS.
 func (w Workflow) Run(workflowFunc WorkflowFunc) {
+	w.RunWithResult(func(params WorkflowParams, cmdb *Client) WorkflowResult {
+		if err := workflowFunc(params, cmdb); err != nil {
+			return WorkflowFailed(err.Error())
+		}
+		return WorkflowSuccess("")
+	})
+}
+
+// Executes a workflow that explicitly returns its result.
+//
+// Preparation of client and parameters is done like in `workflow.Run`.
+// Before, the config is validated (including a reachability check of the api host if enabled
+// with `workflow.SetCheckReachable`) and every problem is logged separately, unknown keys only as warnings.
+// The result is logged as final log line ("Workflow result: <STATUS> - <message>") and
+// failed workflows exit with a non-zero exit code.
+// Failures are logged with level error, all other results with level info.
+//
+// Warnings are written to stdout, so they are reported as SUCCESS_WITH_WARNINGS without marking the workflow
+// as failed in infoCMDB (unless changed with `SetLogStream`).
+// A result without status (e.g. the zero value) is reported as failure.
+func (w Workflow) RunWithResult(workflowFunc WorkflowResultFunc) {
+	logSplitter.setDefaultStream(log.WarnLevel, LOG_STREAM_STDOUT)
+
+	loadedConfig, problems := config.LoadAndValidate(w.config, config.ValidationOptions{
+		CheckReachable:   w.checkReachable,
+		AllowUnknownKeys: true,
+	})
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Errorf("Config problem: %v", problem)
+		}
+		reportWorkflowResult(WorkflowFailed(fmt.Sprintf("invalid config \"%s\"", w.config)))
+		return
+	}
+
 	cmdb := NewClient()
-	cmdbClientErr := cmdb.LoadConfig(w.config)
+	cmdbClientErr := cmdb.applyConfig(loadedConfig)
 	if cmdbClientErr != nil {
-		log.Fatalf("Failed to Login: %v", cmdbClientErr)
+		reportWorkflowResult(WorkflowFailed(fmt.Sprintf("Failed to Login: %v", cmdbClientErr)))
+		return
 	}
 
 	params, parseErr := parseParams()
 	if parseErr != nil {
-		log.Fatal(parseErr)
+		reportWorkflowResult(WorkflowFailed(parseErr.Error()))
+		return
 	}
 
-	workflowErr := workflowFunc(params, cmdb)
-	if workflowErr != nil {
-		log.Fatal(workflowErr)
+	warningsBefore := loggedWarnings.Count()
+	result := workflowFunc(params, cmdb)
+	reportWorkflowResult(result.withLoggedWarnings(loggedWarnings.Count() - warningsBefore))
+}
+
+func reportWorkflowResult(result WorkflowResult) {
+	switch result.Status {
+	case WORKFLOW_STATUS_SUCCESS, WORKFLOW_STATUS_SUCCESS_WITH_WARNINGS, WORKFLOW_STATUS_FAILED:
+	case "":
+		result = WorkflowFailed("workflow returned no result status")
+	default:
+		result = WorkflowFailed(fmt.Sprintf("workflow returned unknown result status \"%s\"", result.Status))
+	}
+
+	if result.Status == WORKFLOW_STATUS_FAILED {
+		log.Error(result.String())
+	} else {
+		log.Info(result.String())
+	}
+
+	if exitCode := result.ExitCode(); exitCode != 0 {
+		exit(exitCode)
 	}
 }
 
@@ -116,22 +254,6 @@ func parseParams() (params WorkflowParams, err error) {
 	return
 }
 
-func (iw *IntWrapper) UnmarshalJSON(b []byte) error {
-	if b[0] != '"' {
-		return json.Unmarshal(b, (*int)(iw))
-	}
-	var s string
-	if err := json.Unmarshal(b, &s); err != nil {
-		return err
-	}
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return err
-	}
-	*iw = IntWrapper(i)
-	return nil
-}
-
 func (c *Client) GetWorkflowContext(workflowInstanceId int) (workflowContext *v2.WorkflowContext, err error) {
 	return c.v2.GetWorkflowContext(workflowInstanceId)
 }
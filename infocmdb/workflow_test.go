@@ -168,3 +168,52 @@ func Test_parseParams(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkflowResult_withLoggedWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   WorkflowResult
+		warnings int
+		want     WorkflowResult
+	}{
+		{"success without warnings", WorkflowSuccess("done"), 0, WorkflowSuccess("done")},
+		{"success with warnings", WorkflowSuccess("done"), 2, WorkflowSuccessWithWarnings("done, 2 warning(s) logged")},
+		{"success without message", WorkflowSuccess(""), 1, WorkflowSuccessWithWarnings("1 warning(s) logged")},
+		{"explicit warnings", WorkflowSuccessWithWarnings("check data"), 1, WorkflowSuccessWithWarnings("check data")},
+		{"failed", WorkflowFailed("error"), 3, WorkflowFailed("error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.withLoggedWarnings(tt.warnings); got != tt.want {
+				t.Errorf("withLoggedWarnings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reportWorkflowResult(t *testing.T) {
+	defer func() { exit = os.Exit }()
+
+	tests := []struct {
+		name         string
+		result       WorkflowResult
+		wantExitCode int
+	}{
+		{"success", WorkflowSuccess(""), 0},
+		{"success with warnings", WorkflowSuccessWithWarnings("1 warning(s) logged"), 0},
+		{"failed", WorkflowFailed("error"), 1},
+		{"no status", WorkflowResult{}, 1},
+		{"unknown status", WorkflowResult{Status: "DONE"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotExitCode := 0
+			exit = func(code int) { gotExitCode = code }
+
+			reportWorkflowResult(tt.result)
+			if gotExitCode != tt.wantExitCode {
+				t.Errorf("reportWorkflowResult() exit code = %v, want %v", gotExitCode, tt.wantExitCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package redact
+
+// Masking of secrets (passwords, tokens, apikeys, ...) before they are written to logs or returned in errors.
+//
+// Sensitive data is detected in two ways:
+//
+// * by key: parameters, struct fields and key/value pairs in text (json, yaml, form encoded, url paths)
+//   whose key contains one of the built-in sensitive words or matches a registered key (e.g. an attribute name)
+//
+// * by value: registered values (e.g. values of password attributes) are masked wherever they appear
+//
+// Additionally json objects describing a ci attribute of type password or with a sensitive attribute name
+// (as returned by the attribute and history webservices) get their value fields masked.
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replacement for every masked value.
+const Mask = "*******"
+
+// Registered values shorter than this are ignored, masking them would garble unrelated log output.
+const minValueLength = 4
+
+// Maximum number of values kept by AddSensitiveValues, the least recently registered values are forgotten first.
+// Secrets registered with AddSecrets don't count towards this limit.
+const MaxSensitiveValues = 1000
+
+// Words that mark a key as sensitive when contained in it (case insensitive).
+var sensitiveWords = []string{
+	"password",
+	"passwd",
+	"token",
+	"apikey",
+	"api_key",
+	"secret",
+	"authorization",
+}
+
+var (
+	mu              sync.RWMutex
+	sensitiveKeys   = map[string]bool{}
+	secretValues    = map[string]bool{}
+	sensitiveValues = map[string]bool{}
+	// registration order of sensitiveValues, oldest first
+	sensitiveValueOrder []string
+	keyValuePattern     = buildKeyValuePattern(nil)
+)
+
+// AddSensitiveKeys registers additional keys (e.g. attribute names) whose values must be masked.
+// Keys are compared case insensitive.
+func AddSensitiveKeys(keys ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		sensitiveKeys[key] = true
+	}
+
+	registered := make([]string, 0, len(sensitiveKeys))
+	for key := range sensitiveKeys {
+		registered = append(registered, key)
+	}
+	keyValuePattern = buildKeyValuePattern(registered)
+}
+
+// AddSecrets registers credentials (passwords, tokens) that must be masked wherever they appear.
+// Unlike values registered with AddSensitiveValues they are never forgotten.
+func AddSecrets(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		if len(value) < minValueLength {
+			continue
+		}
+		secretValues[value] = true
+	}
+}
+
+// AddSensitiveValues registers values (e.g. of password attributes) that must be masked wherever they appear.
+// At most MaxSensitiveValues are kept, registering a known value again marks it as recently used.
+func AddSensitiveValues(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		if len(value) < minValueLength {
+			continue
+		}
+		if sensitiveValues[value] {
+			for i, registered := range sensitiveValueOrder {
+				if registered == value {
+					sensitiveValueOrder = append(sensitiveValueOrder[:i], sensitiveValueOrder[i+1:]...)
+					break
+				}
+			}
+		}
+		sensitiveValues[value] = true
+		sensitiveValueOrder = append(sensitiveValueOrder, value)
+	}
+
+	for len(sensitiveValueOrder) > MaxSensitiveValues {
+		delete(sensitiveValues, sensitiveValueOrder[0])
+		sensitiveValueOrder = sensitiveValueOrder[1:]
+	}
+}
+
+// IsSensitiveKey reports whether values of the given key must be masked.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	return sensitiveKeys[key]
+}
+
+// String masks all registered values and all values of sensitive key/value pairs in the given text.
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	s = maskValues(s)
+	s = maskAttributeObjects(s)
+	return keyValuePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := keyValuePattern.FindStringSubmatch(match)
+		if groups[2] == "" || groups[2] == Mask {
+			return match
+		}
+		return groups[1] + Mask
+	})
+}
+
+var (
+	jsonObjectPattern         = regexp.MustCompile(`\{[^{}]*\}`)
+	passwordAttributePattern  = regexp.MustCompile(`"(?:attribute_type|attributeTypeName)"\s*:\s*"password"`)
+	attributeNamePattern      = regexp.MustCompile(`"(?:attribute_name|name)"\s*:\s*"([^"]*)"`)
+	attributeValueKeysPattern = regexp.MustCompile(`("(?:value|v|value_text|value_default|old_value|new_value)"\s*:\s*")((?:[^"\\]|\\.)+)"`)
+)
+
+// maskAttributeObjects masks the values of json objects describing sensitive attributes,
+// the caller must hold the read lock.
+func maskAttributeObjects(s string) string {
+	return jsonObjectPattern.ReplaceAllStringFunc(s, func(object string) string {
+		sensitive := passwordAttributePattern.MatchString(object)
+		if !sensitive {
+			if name := attributeNamePattern.FindStringSubmatch(object); name != nil {
+				sensitive = sensitiveKeys[strings.ToLower(name[1])]
+			}
+		}
+		if !sensitive {
+			return object
+		}
+
+		return attributeValueKeysPattern.ReplaceAllString(object, `${1}`+Mask+`"`)
+	})
+}
+
+// maskValues replaces all registered values, the caller must hold the read lock.
+func maskValues(s string) string {
+	// replace longest values first, so that values containing other values are fully masked
+	values := make([]string, 0, len(secretValues)+len(sensitiveValues))
+	for value := range secretValues {
+		values = append(values, value)
+	}
+	for value := range sensitiveValues {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		s = strings.Replace(s, value, Mask, -1)
+	}
+
+	return s
+}
+
+// Params returns a copy of the given parameters with all sensitive values masked.
+func Params(params map[string]string) map[string]string {
+	if params == nil {
+		return nil
+	}
+
+	masked := make(map[string]string, len(params))
+	for key, value := range params {
+		if IsSensitiveKey(key) && value != "" {
+			masked[key] = Mask
+		} else {
+			masked[key] = String(value)
+		}
+	}
+	return masked
+}
+
+// Values returns a copy of the given url values with all sensitive values masked.
+func Values(values url.Values) url.Values {
+	if values == nil {
+		return nil
+	}
+
+	masked := make(url.Values, len(values))
+	for key, list := range values {
+		maskedList := make([]string, len(list))
+		for i, value := range list {
+			if IsSensitiveKey(key) && value != "" {
+				maskedList[i] = Mask
+			} else {
+				maskedList[i] = String(value)
+			}
+		}
+		masked[key] = maskedList
+	}
+	return masked
+}
+
+// Struct formats the given value like "%+v" with all sensitive string fields masked.
+// Fields are sensitive if their name or their yaml/json tag is a sensitive key.
+func Struct(v interface{}) string {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return fmt.Sprintf("%+v", v)
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return String(fmt.Sprintf("%+v", value.Interface()))
+	}
+
+	formatted := fmt.Sprintf("%+v", maskStruct(value).Interface())
+
+	mu.RLock()
+	defer mu.RUnlock()
+	return maskValues(formatted)
+}
+
+func maskStruct(value reflect.Value) reflect.Value {
+	masked := reflect.New(value.Type()).Elem()
+	masked.Set(value)
+
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		field := masked.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			if field.String() != "" && isSensitiveField(structField) {
+				field.SetString(Mask)
+			}
+		case reflect.Struct:
+			field.Set(maskStruct(field))
+		}
+	}
+
+	return masked
+}
+
+func isSensitiveField(field reflect.StructField) bool {
+	if IsSensitiveKey(field.Name) {
+		return true
+	}
+
+	for _, tagName := range []string{"yaml", "json"} {
+		tag := strings.Split(field.Tag.Get(tagName), ",")[0]
+		if tag != "" && tag != "-" && IsSensitiveKey(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildKeyValuePattern matches `key: value`, `"key":"value"`, `key=value` and `/key/value` pairs.
+// The first group contains everything up to the value, the second group contains the value.
+func buildKeyValuePattern(registeredKeys []string) *regexp.Regexp {
+	keyAlternatives := []string{`[\w.-]*(?:` + strings.Join(sensitiveWords, "|") + `)[\w.-]*`}
+	for _, key := range registeredKeys {
+		keyAlternatives = append(keyAlternatives, regexp.QuoteMeta(key))
+	}
+
+	return regexp.MustCompile(`(?i)((?:^|[^\w.-])["']?(?:` + strings.Join(keyAlternatives, "|") + `)["']?(?:[ \t]*[:=][ \t]*|/)["']?)([^"'&\s,;}\]/]*)`)
+}
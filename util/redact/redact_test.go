@@ -0,0 +1,136 @@
+package redact
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	AddSensitiveKeys("emp_pin")
+	AddSensitiveValues("registered-secret", "abc")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "yaml password",
+			in:   "apiUrl: http://localhost\napiUser: admin\napiPassword: admin\n",
+			want: "apiUrl: http://localhost\napiUser: admin\napiPassword: " + Mask + "\n",
+		},
+		{
+			name: "json token",
+			in:   `{"success":true,"data":{"token":"eyJhbGciOi"}}`,
+			want: `{"success":true,"data":{"token":"` + Mask + `"}}`,
+		},
+		{
+			name: "form encoded apikey",
+			in:   "apikey=0123456789&argv1=1",
+			want: "apikey=" + Mask + "&argv1=1",
+		},
+		{
+			name: "url path password",
+			in:   "Get http://cmdb/api/login/username/admin/password/s3cr3t/timeout/600/method/json: refused",
+			want: "Get http://cmdb/api/login/username/admin/password/" + Mask + "/timeout/600/method/json: refused",
+		},
+		{
+			name: "registered key",
+			in:   "emp_pin: 4711",
+			want: "emp_pin: " + Mask,
+		},
+		{
+			name: "registered value",
+			in:   "failed to set value registered-secret",
+			want: "failed to set value " + Mask,
+		},
+		{
+			name: "short values are not registered",
+			in:   "abc",
+			want: "abc",
+		},
+		{
+			name: "password attribute row",
+			in:   `{"ci_id":"1","attribute_name":"db_pass","attribute_type":"password","value":"hunter22"}`,
+			want: `{"ci_id":"1","attribute_name":"db_pass","attribute_type":"password","value":"` + Mask + `"}`,
+		},
+		{
+			name: "sensitive attribute row",
+			in:   `{"ci_id":"1","attribute_name":"emp_pin","attribute_type":"input","value":"4711"}`,
+			want: `{"ci_id":"1","attribute_name":"emp_pin","attribute_type":"input","value":"` + Mask + `"}`,
+		},
+		{
+			name: "password attribute history row",
+			in:   `{"history_id":"1","attribute_name":"db_pass","attribute_type":"password","old_value":"hunter22","new_value":"hunter23"}`,
+			want: `{"history_id":"1","attribute_name":"db_pass","attribute_type":"password","old_value":"` + Mask + `","new_value":"` + Mask + `"}`,
+		},
+		{
+			name: "nothing sensitive",
+			in:   `{"ci_id":"1","attribute_name":"emp_firstname","value":"Homer"}`,
+			want: `{"ci_id":"1","attribute_name":"emp_firstname","value":"Homer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.in); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	got := Params(map[string]string{"username": "admin", "password": "admin", "argv1": "1"})
+	want := map[string]string{"username": "admin", "password": Mask, "argv1": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Params() = %v, want %v", got, want)
+	}
+
+	gotValues := Values(url.Values{"apikey": {"0123456789"}, "argv1": {"1"}})
+	wantValues := url.Values{"apikey": {Mask}, "argv1": {"1"}}
+	if !reflect.DeepEqual(gotValues, wantValues) {
+		t.Errorf("Values() = %v, want %v", gotValues, wantValues)
+	}
+}
+
+func TestAddSensitiveValues(t *testing.T) {
+	AddSecrets("config-password")
+	AddSensitiveValues("oldest-value", "recent-value")
+	for i := 0; i < MaxSensitiveValues-1; i++ {
+		AddSensitiveValues(fmt.Sprintf("value-%04d", i))
+		if i == 0 {
+			AddSensitiveValues("recent-value")
+		}
+	}
+
+	got := String("config-password oldest-value recent-value value-0000")
+	want := Mask + " oldest-value " + Mask + " " + Mask
+	if got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	type nested struct {
+		Secret string
+	}
+	type config struct {
+		ApiUrl   string `yaml:"apiUrl"`
+		Password string `yaml:"apiPassword"`
+		Key      string `yaml:"apiKey"`
+		Empty    string `yaml:"apiToken"`
+		Nested   nested
+	}
+
+	in := &config{ApiUrl: "http://localhost", Password: "admin", Key: "0123", Nested: nested{Secret: "x"}}
+	want := "{ApiUrl:http://localhost Password:" + Mask + " Key:" + Mask + " Empty: Nested:{Secret:" + Mask + "}}"
+	if got := Struct(in); got != want {
+		t.Errorf("Struct() = %v, want %v", got, want)
+	}
+
+	if in.Password != "admin" {
+		t.Errorf("Struct() modified the original value")
+	}
+}
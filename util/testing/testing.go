@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 
 	"gopkg.in/yaml.v2"
 
@@ -16,6 +17,7 @@ import (
 
 type Testing struct {
 	mocking       bool
+	mutex         sync.RWMutex
 	mockings      map[string]mockingResponse
 	mockingServer *httptest.Server
 	url           string
@@ -135,6 +137,26 @@ func (t *Testing) setupMocking() {
 		ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"ci_id":"1","ci_type_id":"1","ci_type":"demo","project":"springfield","project_id":"4"}]}`,
 	})
 
+	for name, attributeType := range map[string]string{
+		"emp_email":            "input",
+		"emp_firstname":        "input",
+		"emp_lastname":         "input",
+		"emp_personnel_number": "input",
+		"emp_phone":            "input",
+		"emp_staff_number":     "input",
+		"emp_password":         "password",
+	} {
+		t.AddMocking(Mocking{
+			RequestString: `PUT##/apiV2/query/execute/int_getAttributeTypeByAttributeName##{"query":{"params":{"argv1":"` + name + `"}}}`,
+			ReturnString:  `{"success":true,"message":"Query executed successfully","data":[{"name":"` + attributeType + `"}]}`,
+		})
+	}
+
+	t.AddMocking(Mocking{
+		RequestString: `PUT##/apiV2/query/execute/int_getAttributeTypeByAttributeName##{"query":{"params":{"argv1":"emp_lastname_NOT_EXISTING"}}}`,
+		ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
+	})
+
 	t.AddMocking(Mocking{
 		RequestString: `PUT##/apiV2/ci/14##{"ci":{"attributes":[{"mode":"set","name":"emp_firstname","value":"22322","ciAttributeId":0,"uploadId":""}]}}`,
 		ReturnString:  `{"success":true,"message":"Query executed successfully","data":[]}`,
@@ -184,6 +206,9 @@ type mockingResponse struct {
 }
 
 func (t *Testing) AddMocking(m Mocking) *Testing {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	t.mockings[m.RequestString] = mockingResponse{
 		ReturnString: m.ReturnString,
 		ContentType:  m.ContentType,
@@ -202,7 +227,11 @@ func (t *Testing) newMockServer() *httptest.Server {
 		}
 		mockString := fmt.Sprintf("%s##%s##%s", r.Method, r.URL.String(), string(body))
 
-		if m, ok := t.mockings[mockString]; ok {
+		t.mutex.RLock()
+		m, ok := t.mockings[mockString]
+		t.mutex.RUnlock()
+
+		if ok {
 			if m.ContentType == "" {
 				m.ContentType = "application/json;charset=UTF-8"
 			}